@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter/config"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+// APIConfig configures the Datadog API key used to validate requests and,
+// by default, to authenticate every payload type below.
+type APIConfig struct {
+	// Key is the Datadog API key.
+	Key string `mapstructure:"key"`
+	// Site is the Datadog site to send data to, e.g. datadoghq.com or
+	// datadoghq.eu.
+	Site string `mapstructure:"site"`
+	// FailOnInvalidKey, if true, makes the exporter fail to start rather
+	// than merely logging a warning when the API key cannot be validated.
+	FailOnInvalidKey bool `mapstructure:"fail_on_invalid_key"`
+}
+
+// MetricsConfig configures delivery of the exporter's own running
+// hostname/tags metrics.
+type MetricsConfig struct {
+	TCPAddr confignet.TCPAddrConfig `mapstructure:",squash"`
+}
+
+// TracesConfig configures how incoming OTLP spans are translated and
+// forwarded to the Datadog trace-agent.
+type TracesConfig struct {
+	// Endpoint overrides where traces (and, unless split, APM stats) are
+	// sent. Defaults to the site derived from API.Site.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// SpanNameRemappings maps span operation names to the name Datadog
+	// should display instead.
+	SpanNameRemappings map[string]string `mapstructure:"span_name_remappings"`
+	// SpanNameAsResourceName uses the span name, rather than the
+	// Datadog-specific resource-naming heuristics, as the APM resource name.
+	SpanNameAsResourceName bool `mapstructure:"span_name_as_resource_name"`
+	// IgnoreResources lists resource names to drop entirely from the traces
+	// sent to Datadog, e.g. health check endpoints.
+	IgnoreResources []string `mapstructure:"ignore_resources"`
+}
+
+// HostMetadataConfig configures whether the exporter pushes host metadata
+// (OS, hostname tags, etc.) in addition to the data it translates.
+type HostMetadataConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// Config defines configuration for the Datadog exporter.
+type Config struct {
+	API APIConfig `mapstructure:"api"`
+
+	Metrics MetricsConfig `mapstructure:"metrics"`
+	Traces  TracesConfig  `mapstructure:"traces"`
+
+	// Hostname is the host name to use for metrics and traces that don't
+	// already carry one. Detected automatically if unset.
+	Hostname string `mapstructure:"hostname"`
+
+	HostMetadata HostMetadataConfig `mapstructure:"host_metadata"`
+
+	// SplitEndpoints, when set, routes traces, APM stats and running
+	// metrics to independent Datadog backends instead of the single site
+	// derived from API.Key/Traces.Endpoint/Metrics. See
+	// SplitEndpointsConfig for the caveat on APM stats.
+	SplitEndpoints *SplitEndpointsConfig `mapstructure:"split_endpoints"`
+
+	// Retry configures the backoff applied to the exporter's own outbound
+	// API calls (currently the running hostname/tags metrics post).
+	Retry RetryConfig `mapstructure:"retry"`
+
+	// Telemetry controls the exporter's self-observability spans/metrics.
+	Telemetry TelemetryConfig `mapstructure:"telemetry"`
+}
+
+// Unmarshal decodes conf into c and fills in defaults, so callers always see
+// a fully-populated Config regardless of which sections the user set.
+func (c *Config) Unmarshal(conf *confmap.Conf) error {
+	if err := conf.Unmarshal(c, confmap.WithErrorUnused()); err != nil {
+		return err
+	}
+
+	if !conf.IsSet("retry") {
+		c.Retry = DefaultRetryConfig()
+	}
+	if !conf.IsSet("telemetry::enabled") {
+		c.Telemetry.Enabled = true
+	}
+	return nil
+}
+
+// Validate checks that c is self-consistent and ready for use.
+func (c *Config) Validate() error {
+	split := c.SplitEndpoints
+	if c.API.Key == "" && !(split != nil && split.Enabled && split.fullyOverridesAPIKey()) {
+		return fmt.Errorf("api.key is required")
+	}
+
+	if split != nil && split.Enabled {
+		if err := split.Validate(); err != nil {
+			return fmt.Errorf("split_endpoints: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func TestConfigValidate(t *testing.T) {
+	t.Run("requires api.key by default", func(t *testing.T) {
+		cfg := &Config{}
+		require.ErrorContains(t, cfg.Validate(), "api.key is required")
+	})
+
+	t.Run("api.key set is sufficient", func(t *testing.T) {
+		cfg := &Config{API: APIConfig{Key: "key"}}
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("split_endpoints fully overriding api key waives api.key", func(t *testing.T) {
+		cfg := &Config{
+			SplitEndpoints: &SplitEndpointsConfig{
+				Enabled: true,
+				Traces:  EndpointConfig{APIKey: "t", Endpoint: "https://traces.example.com"},
+				Stats:   EndpointConfig{APIKey: "s", Endpoint: "https://stats.example.com"},
+				Metrics: EndpointConfig{APIKey: "m", Endpoint: "https://metrics.example.com"},
+			},
+		}
+		require.NoError(t, cfg.Validate())
+	})
+
+	t.Run("split_endpoints not fully overriding api key still requires api.key", func(t *testing.T) {
+		cfg := &Config{
+			SplitEndpoints: &SplitEndpointsConfig{
+				Enabled: true,
+				Traces:  EndpointConfig{APIKey: "t", Endpoint: "https://traces.example.com"},
+			},
+		}
+		require.ErrorContains(t, cfg.Validate(), "api.key is required")
+	})
+
+	t.Run("propagates split_endpoints validation errors", func(t *testing.T) {
+		cfg := &Config{
+			API: APIConfig{Key: "key"},
+			SplitEndpoints: &SplitEndpointsConfig{
+				Enabled: true,
+				Traces:  EndpointConfig{APIKey: "t"},
+			},
+		}
+		err := cfg.Validate()
+		require.ErrorContains(t, err, "split_endpoints:")
+		require.ErrorContains(t, err, "traces.endpoint must be set")
+	})
+
+	t.Run("disabled split_endpoints is not validated", func(t *testing.T) {
+		cfg := &Config{
+			API:            APIConfig{Key: "key"},
+			SplitEndpoints: &SplitEndpointsConfig{Traces: EndpointConfig{APIKey: "t"}},
+		}
+		require.NoError(t, cfg.Validate())
+	})
+}
+
+func TestConfigUnmarshalDefaults(t *testing.T) {
+	t.Run("fills in retry defaults when unset", func(t *testing.T) {
+		cfg := &Config{}
+		conf := confmap.NewFromStringMap(map[string]interface{}{"api": map[string]interface{}{"key": "key"}})
+		require.NoError(t, cfg.Unmarshal(conf))
+		require.Equal(t, DefaultRetryConfig(), cfg.Retry)
+	})
+
+	t.Run("leaves retry as configured when set", func(t *testing.T) {
+		cfg := &Config{}
+		conf := confmap.NewFromStringMap(map[string]interface{}{
+			"retry": map[string]interface{}{"enabled": false},
+		})
+		require.NoError(t, cfg.Unmarshal(conf))
+		require.False(t, cfg.Retry.Enabled)
+		require.NotEqual(t, DefaultRetryConfig(), cfg.Retry)
+	})
+
+	t.Run("defaults telemetry.enabled to true when unset", func(t *testing.T) {
+		cfg := &Config{}
+		conf := confmap.NewFromStringMap(map[string]interface{}{})
+		require.NoError(t, cfg.Unmarshal(conf))
+		require.True(t, cfg.Telemetry.Enabled)
+	})
+
+	t.Run("honors an explicit telemetry.enabled=false", func(t *testing.T) {
+		cfg := &Config{}
+		conf := confmap.NewFromStringMap(map[string]interface{}{
+			"telemetry": map[string]interface{}{"enabled": false},
+		})
+		require.NoError(t, cfg.Unmarshal(conf))
+		require.False(t, cfg.Telemetry.Enabled)
+	})
+}
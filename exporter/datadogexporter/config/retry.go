@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter/config"
+
+import "time"
+
+// RetryConfig configures the exponential backoff applied to outbound API
+// calls that the exporter makes directly (currently the "running"
+// hostname/tags metrics posted via MetricsSink).
+type RetryConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// InitialInterval is the time to wait before the first retry.
+	InitialInterval time.Duration `mapstructure:"initial_interval"`
+	// MaxInterval caps the interval between retries.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// MaxElapsedTime bounds the total time spent retrying before giving up.
+	// Zero means retry forever (until ctx is canceled).
+	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+	// RandomizationFactor jitters each interval by +/- this fraction.
+	RandomizationFactor float64 `mapstructure:"randomization_factor"`
+}
+
+// DefaultRetryConfig returns the recommended backoff settings.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		Enabled:             true,
+		InitialInterval:     5 * time.Second,
+		MaxInterval:         30 * time.Second,
+		MaxElapsedTime:      5 * time.Minute,
+		RandomizationFactor: 0.5,
+	}
+}
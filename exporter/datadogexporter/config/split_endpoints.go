@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter/config"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// EndpointConfig configures delivery of a single Datadog intake payload type
+// (traces, APM stats, or the exporter's own running metrics), independent of
+// the other payload types.
+type EndpointConfig struct {
+	// APIKey overrides the top-level API.Key for this payload type.
+	APIKey string `mapstructure:"api_key"`
+
+	// Endpoint is the Datadog intake URL for this payload type, e.g.
+	// https://trace.agent.datadoghq.com.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// HTTPClientSettings carries TLS, proxy and compression settings to use
+	// when delivering this payload type. Currently only the "metrics"
+	// endpoint honors any of it (Timeout only); traces and apm_stats are
+	// delivered by the trace-agent's own client and reject a non-zero
+	// HTTPClientSettings at validation time. See SplitEndpointsConfig.Validate.
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
+}
+
+// SplitEndpointsConfig lets traces, APM stats and running metrics be routed
+// to independent Datadog backends. When Enabled is false, all three continue
+// to share the single site derived from API.Key/Traces.Endpoint/Metrics.
+//
+// Traces and running metrics are genuinely independent: Traces overrides the
+// trace-agent's single delivery endpoint outright, and Metrics is delivered
+// through its own client entirely outside the trace-agent. Stats is not: the
+// vendored trace-agent (github.com/DataDog/datadog-agent/pkg/trace/agent)
+// fans every configured endpoint the same traces+stats payload rather than
+// letting APM stats be addressed separately from traces, so enabling Stats
+// without Traces dual-writes traces to the stats endpoint too. See the
+// comment on newTracesExporter's endpoint wiring for how this is surfaced.
+type SplitEndpointsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	Traces  EndpointConfig `mapstructure:"traces"`
+	Stats   EndpointConfig `mapstructure:"apm_stats"`
+	Metrics EndpointConfig `mapstructure:"metrics"`
+}
+
+// fullyOverridesAPIKey reports whether every split destination carries its
+// own api_key, making the top-level API.Key unused.
+func (c *SplitEndpointsConfig) fullyOverridesAPIKey() bool {
+	return c.Traces.APIKey != "" && c.Stats.APIKey != "" && c.Metrics.APIKey != ""
+}
+
+// Validate checks that every endpoint configured to receive split traffic
+// actually carries a destination.
+func (c *SplitEndpointsConfig) Validate() error {
+	for name, ep := range map[string]EndpointConfig{"traces": c.Traces, "apm_stats": c.Stats, "metrics": c.Metrics} {
+		if ep.APIKey != "" && ep.Endpoint == "" {
+			return fmt.Errorf("%s.endpoint must be set when %s.api_key is set", name, name)
+		}
+	}
+
+	// metrics.{endpoint,api_key,timeout} are wired to the running-metrics
+	// client (see newTracesExporter); nothing else on HTTPClientSettings is,
+	// so fail loudly here rather than silently dropping TLS/proxy/header
+	// settings a user configured expecting them to take effect.
+	if unsupportedHTTPClientSettings(c.Metrics.HTTPClientSettings) {
+		return fmt.Errorf("split_endpoints.metrics: only endpoint, api_key and timeout are currently supported; " +
+			"tls, proxy_url, headers and compression are not applied to the running-metrics client")
+	}
+
+	// traces.{endpoint,api_key} and apm_stats.{endpoint,api_key} are wired
+	// into the trace-agent's Endpoints list (see newTracesExporter); nothing
+	// else on HTTPClientSettings is, not even Timeout, since the trace-agent
+	// owns its own HTTP client for that delivery path.
+	for name, ep := range map[string]EndpointConfig{"traces": c.Traces, "apm_stats": c.Stats} {
+		if anyHTTPClientSettingsSet(ep.HTTPClientSettings) {
+			return fmt.Errorf("split_endpoints.%s: http client settings (timeout, tls, proxy_url, headers, compression) "+
+				"are not applied to trace-agent delivery; only endpoint and api_key are supported", name)
+		}
+	}
+
+	return nil
+}
+
+// unsupportedHTTPClientSettings reports whether s sets a proxy URL, headers,
+// compression, or TLS insecure/CA/cert/key settings, none of which split
+// metrics delivery honors. Other HTTPClientSettings fields are not checked.
+func unsupportedHTTPClientSettings(s confighttp.HTTPClientSettings) bool {
+	return s.ProxyURL != "" ||
+		len(s.Headers) > 0 ||
+		s.Compression != "" ||
+		s.TLSSetting.Insecure ||
+		s.TLSSetting.CAFile != "" ||
+		s.TLSSetting.CertFile != "" ||
+		s.TLSSetting.KeyFile != ""
+}
+
+// anyHTTPClientSettingsSet reports whether s sets Timeout or any field
+// checked by unsupportedHTTPClientSettings, for endpoints where nothing on
+// HTTPClientSettings is currently wired.
+func anyHTTPClientSettingsSet(s confighttp.HTTPClientSettings) bool {
+	return s.Timeout != 0 || unsupportedHTTPClientSettings(s)
+}
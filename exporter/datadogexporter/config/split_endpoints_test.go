@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+func TestSplitEndpointsConfigFullyOverridesAPIKey(t *testing.T) {
+	t.Run("all three set", func(t *testing.T) {
+		c := &SplitEndpointsConfig{
+			Traces:  EndpointConfig{APIKey: "t"},
+			Stats:   EndpointConfig{APIKey: "s"},
+			Metrics: EndpointConfig{APIKey: "m"},
+		}
+		require.True(t, c.fullyOverridesAPIKey())
+	})
+
+	t.Run("one missing", func(t *testing.T) {
+		c := &SplitEndpointsConfig{
+			Traces: EndpointConfig{APIKey: "t"},
+			Stats:  EndpointConfig{APIKey: "s"},
+		}
+		require.False(t, c.fullyOverridesAPIKey())
+	})
+}
+
+func TestSplitEndpointsConfigValidate(t *testing.T) {
+	t.Run("ok when unset", func(t *testing.T) {
+		require.NoError(t, (&SplitEndpointsConfig{}).Validate())
+	})
+
+	t.Run("rejects api_key without endpoint", func(t *testing.T) {
+		c := &SplitEndpointsConfig{Traces: EndpointConfig{APIKey: "t"}}
+		require.ErrorContains(t, c.Validate(), "traces.endpoint must be set when traces.api_key is set")
+	})
+
+	t.Run("rejects unsupported http client settings on metrics", func(t *testing.T) {
+		c := &SplitEndpointsConfig{
+			Metrics: EndpointConfig{
+				HTTPClientSettings: confighttp.HTTPClientSettings{ProxyURL: "http://proxy.example.com"},
+			},
+		}
+		require.ErrorContains(t, c.Validate(), "split_endpoints.metrics: only endpoint, api_key and timeout")
+	})
+
+	t.Run("allows timeout on metrics", func(t *testing.T) {
+		c := &SplitEndpointsConfig{
+			Metrics: EndpointConfig{HTTPClientSettings: confighttp.HTTPClientSettings{Timeout: 1}},
+		}
+		require.NoError(t, c.Validate())
+	})
+
+	t.Run("rejects any http client settings on traces", func(t *testing.T) {
+		c := &SplitEndpointsConfig{
+			Traces: EndpointConfig{
+				Endpoint:           "https://traces.example.com",
+				HTTPClientSettings: confighttp.HTTPClientSettings{Timeout: 1},
+			},
+		}
+		require.ErrorContains(t, c.Validate(), "split_endpoints.traces: http client settings")
+	})
+
+	t.Run("rejects any http client settings on apm_stats", func(t *testing.T) {
+		c := &SplitEndpointsConfig{
+			Stats: EndpointConfig{
+				Endpoint:           "https://stats.example.com",
+				HTTPClientSettings: confighttp.HTTPClientSettings{Timeout: 1},
+			},
+		}
+		require.ErrorContains(t, c.Validate(), "split_endpoints.apm_stats: http client settings")
+	})
+}
+
+func TestUnsupportedHTTPClientSettings(t *testing.T) {
+	require.False(t, unsupportedHTTPClientSettings(confighttp.HTTPClientSettings{}))
+	require.False(t, unsupportedHTTPClientSettings(confighttp.HTTPClientSettings{Timeout: 1}))
+	require.True(t, unsupportedHTTPClientSettings(confighttp.HTTPClientSettings{ProxyURL: "http://proxy.example.com"}))
+}
+
+func TestAnyHTTPClientSettingsSet(t *testing.T) {
+	require.False(t, anyHTTPClientSettingsSet(confighttp.HTTPClientSettings{}))
+	require.True(t, anyHTTPClientSettingsSet(confighttp.HTTPClientSettings{Timeout: 1}))
+	require.True(t, anyHTTPClientSettingsSet(confighttp.HTTPClientSettings{ProxyURL: "http://proxy.example.com"}))
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter/config"
+
+// TelemetryConfig controls the exporter's own self-observability: spans and
+// metrics recorded through the collector's MeterProvider/TracerProvider,
+// describing the exporter's own behavior rather than the data it exports.
+type TelemetryConfig struct {
+	// Enabled turns on self-observability spans and metrics. Defaults to true.
+	Enabled bool `mapstructure:"enabled"`
+}
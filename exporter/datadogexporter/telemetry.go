@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadogexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter"
+
+// exporterTelemetry records the exporter's own spans and metrics through the
+// collector's MeterProvider/TracerProvider, so operators can observe the
+// exporter's behavior using the collector's normal telemetry pipeline
+// instead of scraping its logs.
+type exporterTelemetry struct {
+	enabled bool
+
+	tracer trace.Tracer
+
+	acceptedSpans          metric.Int64Counter
+	droppedSpans           metric.Int64Counter
+	bytesForwarded         metric.Int64Counter
+	metricPostRetries      metric.Int64Counter
+	metricPostFailures     metric.Int64Counter
+	apiKeyValidationErrors metric.Int64Counter
+	receiveLatency         metric.Float64Histogram
+}
+
+func newExporterTelemetry(set component.TelemetrySettings, enabled bool) (*exporterTelemetry, error) {
+	if !enabled {
+		return &exporterTelemetry{enabled: false}, nil
+	}
+
+	meter := set.MeterProvider.Meter(instrumentationName)
+
+	acceptedSpans, err := meter.Int64Counter(
+		"otelcol_exporter_datadog_accepted_spans",
+		metric.WithDescription("Number of spans successfully forwarded to the Datadog trace agent"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create accepted spans counter: %w", err)
+	}
+
+	droppedSpans, err := meter.Int64Counter(
+		"otelcol_exporter_datadog_dropped_spans",
+		metric.WithDescription("Number of spans dropped because the trace agent could not process them"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dropped spans counter: %w", err)
+	}
+
+	bytesForwarded, err := meter.Int64Counter(
+		"otelcol_exporter_datadog_bytes_forwarded",
+		metric.WithDescription("Serialized size of the resource spans successfully handed to the Datadog trace agent"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bytes forwarded counter: %w", err)
+	}
+
+	metricPostRetries, err := meter.Int64Counter(
+		"otelcol_exporter_datadog_metric_post_retries",
+		metric.WithDescription("Number of retried running-metrics POST attempts"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric post retries counter: %w", err)
+	}
+
+	metricPostFailures, err := meter.Int64Counter(
+		"otelcol_exporter_datadog_metric_post_failures",
+		metric.WithDescription("Number of running-metrics POSTs dropped after failing, whether or not they were retried"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric post failures counter: %w", err)
+	}
+
+	apiKeyValidationErrors, err := meter.Int64Counter(
+		"otelcol_exporter_datadog_api_key_validation_failures",
+		metric.WithDescription("Number of API key validation failures"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key validation failures counter: %w", err)
+	}
+
+	receiveLatency, err := meter.Float64Histogram(
+		"otelcol_exporter_datadog_receive_resource_spans_duration",
+		metric.WithDescription("Time taken to hand a single ResourceSpans to the trace agent's OTLP receiver"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create receive latency histogram: %w", err)
+	}
+
+	return &exporterTelemetry{
+		enabled:                true,
+		tracer:                 set.TracerProvider.Tracer(instrumentationName),
+		acceptedSpans:          acceptedSpans,
+		droppedSpans:           droppedSpans,
+		bytesForwarded:         bytesForwarded,
+		metricPostRetries:      metricPostRetries,
+		metricPostFailures:     metricPostFailures,
+		apiKeyValidationErrors: apiKeyValidationErrors,
+		receiveLatency:         receiveLatency,
+	}, nil
+}
+
+// startConsumeTracesSpan starts a span around a single consumeTraces
+// invocation, annotated with the number of resource spans it carries.
+func (t *exporterTelemetry) startConsumeTracesSpan(ctx context.Context, resourceSpanCount int) (context.Context, trace.Span) {
+	if !t.enabled {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	return t.tracer.Start(ctx, "datadogexporter.consumeTraces",
+		trace.WithAttributes(attribute.Int("resource_spans.count", resourceSpanCount)))
+}
+
+func (t *exporterTelemetry) recordHostCardinality(span trace.Span, hostCount, tagCount int) {
+	if !t.enabled {
+		return
+	}
+	span.SetAttributes(
+		attribute.Int("datadog.hosts", hostCount),
+		attribute.Int("datadog.tags", tagCount),
+	)
+}
+
+func (t *exporterTelemetry) addAcceptedSpans(ctx context.Context, n int64) {
+	if !t.enabled {
+		return
+	}
+	t.acceptedSpans.Add(ctx, n)
+}
+
+func (t *exporterTelemetry) addDroppedSpans(ctx context.Context, n int64) {
+	if !t.enabled {
+		return
+	}
+	t.droppedSpans.Add(ctx, n)
+}
+
+func (t *exporterTelemetry) addBytesForwarded(ctx context.Context, n int64) {
+	if !t.enabled {
+		return
+	}
+	t.bytesForwarded.Add(ctx, n)
+}
+
+func (t *exporterTelemetry) addMetricPostRetry(ctx context.Context) {
+	if !t.enabled {
+		return
+	}
+	t.metricPostRetries.Add(ctx, 1)
+}
+
+func (t *exporterTelemetry) addMetricPostFailure(ctx context.Context) {
+	if !t.enabled {
+		return
+	}
+	t.metricPostFailures.Add(ctx, 1)
+}
+
+func (t *exporterTelemetry) addAPIKeyValidationError(ctx context.Context) {
+	if !t.enabled {
+		return
+	}
+	t.apiKeyValidationErrors.Add(ctx, 1)
+}
+
+func (t *exporterTelemetry) recordReceiveLatency(ctx context.Context, seconds float64) {
+	if !t.enabled {
+		return
+	}
+	t.receiveLatency.Record(ctx, seconds)
+}
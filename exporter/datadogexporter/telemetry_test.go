@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadogexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+)
+
+func TestNewExporterTelemetryDisabled(t *testing.T) {
+	telemetry, err := newExporterTelemetry(component.TelemetrySettings{}, false)
+	require.NoError(t, err)
+	require.False(t, telemetry.enabled)
+
+	// None of the gated recorders should touch the nil instruments left
+	// behind when disabled, since every one of them checks t.enabled first.
+	ctx := context.Background()
+	require.NotPanics(t, func() {
+		telemetry.addAcceptedSpans(ctx, 1)
+		telemetry.addDroppedSpans(ctx, 1)
+		telemetry.addBytesForwarded(ctx, 1)
+		telemetry.addMetricPostRetry(ctx)
+		telemetry.addMetricPostFailure(ctx)
+		telemetry.addAPIKeyValidationError(ctx)
+		telemetry.recordReceiveLatency(ctx, 1.0)
+	})
+
+	gotCtx, span := telemetry.startConsumeTracesSpan(ctx, 3)
+	require.Equal(t, ctx, gotCtx)
+	require.NotPanics(t, func() {
+		telemetry.recordHostCardinality(span, 1, 2)
+	})
+}
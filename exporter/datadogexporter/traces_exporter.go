@@ -16,15 +16,19 @@ package datadogexporter // import "github.com/open-telemetry/opentelemetry-colle
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/trace/agent"
 	traceconfig "github.com/DataDog/datadog-agent/pkg/trace/config"
 	tracelog "github.com/DataDog/datadog-agent/pkg/trace/log"
+	"github.com/cenkalti/backoff/v4"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -38,23 +42,63 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter/internal/utils"
 )
 
+// MetricsSink posts the exporter's own "running" hostname/tags series to a
+// Datadog metrics intake. Satisfied by *datadog.Client, and split out as an
+// interface so traces and running metrics no longer have to share a backend.
+type MetricsSink interface {
+	PostMetrics(series []datadog.Metric) error
+}
+
 type traceExporter struct {
 	params       component.ExporterCreateSettings
 	cfg          *config.Config
 	ctx          context.Context // ctx triggers shutdown upon cancellation
-	client       *datadog.Client // client sends runnimg metrics to backend & performs API validation
-	scrubber     scrub.Scrubber  // scrubber scrubs sensitive information from error messages
-	onceMetadata *sync.Once      // onceMetadata ensures that metadata is sent only once across all exporters
-	wg           sync.WaitGroup  // wg waits for graceful shutdown
-	agent        *agent.Agent    // agent processes incoming traces
+	metricsSink  MetricsSink     // metricsSink sends running metrics to its own backend & performs API validation
+	retryCfg     config.RetryConfig
+	scrubber     scrub.Scrubber // scrubber scrubs sensitive information from error messages
+	onceMetadata *sync.Once     // onceMetadata ensures that metadata is sent only once across all exporters
+	wg           sync.WaitGroup // wg waits for graceful shutdown
+	agent        *agent.Agent   // agent processes incoming traces; traces may be routed independently of metricsSink via cfg.SplitEndpoints, but apm_stats can only add a dual-write destination (see newTracesExporter)
+	telemetry    *exporterTelemetry
 }
 
 func newTracesExporter(ctx context.Context, params component.ExporterCreateSettings, cfg *config.Config, onceMetadata *sync.Once) (*traceExporter, error) {
+	split := cfg.SplitEndpoints
+
 	// client to send running metric to the backend & perform API key validation
-	client := utils.CreateClient(cfg.API.Key, cfg.Metrics.TCPAddr.Endpoint)
-	if err := utils.ValidateAPIKey(params.Logger, client); err != nil && cfg.API.FailOnInvalidKey {
-		return nil, err
+	metricsKey, metricsAddr := cfg.API.Key, cfg.Metrics.TCPAddr.Endpoint
+	metricsHTTPClientSettings := confighttp.HTTPClientSettings{}
+	if split != nil && split.Enabled && split.Metrics.Endpoint != "" {
+		metricsAddr = split.Metrics.Endpoint
+		if split.Metrics.APIKey != "" {
+			metricsKey = split.Metrics.APIKey
+		}
+		metricsHTTPClientSettings = split.Metrics.HTTPClientSettings
+	}
+	telemetry, err := newExporterTelemetry(params.TelemetrySettings, cfg.Telemetry.Enabled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up exporter telemetry: %w", err)
+	}
+
+	metricsClient := utils.CreateClient(metricsKey, metricsAddr)
+	// utils.CreateClient builds the running-metrics client with a default
+	// *http.Client; apply the split endpoint's timeout on top of it so
+	// EndpointConfig.HTTPClientSettings isn't silently ignored for the one
+	// payload type (running metrics) that goes through a client we fully
+	// own here. TLS/proxy settings still require utils.CreateClient itself
+	// to accept a custom *http.Client, which it does not.
+	if metricsHTTPClientSettings.Timeout > 0 {
+		if dc, ok := metricsClient.(*datadog.Client); ok && dc.HttpClient != nil {
+			dc.HttpClient.Timeout = metricsHTTPClientSettings.Timeout
+		}
+	}
+	if err := utils.ValidateAPIKey(params.Logger, metricsClient); err != nil {
+		telemetry.addAPIKeyValidationError(ctx)
+		if cfg.API.FailOnInvalidKey {
+			return nil, err
+		}
 	}
+
 	acfg := traceconfig.New()
 	acfg.AgentVersion = fmt.Sprintf("datadogexporter-%s-%s", params.BuildInfo.Command, params.BuildInfo.Version)
 	acfg.Hostname = metadata.GetHost(params.Logger, cfg.Hostname)
@@ -66,14 +110,47 @@ func newTracesExporter(ctx context.Context, params component.ExporterCreateSetti
 	if addr := cfg.Traces.Endpoint; addr != "" {
 		acfg.Endpoints[0].Host = addr
 	}
+	if split != nil && split.Enabled && split.Traces.Endpoint != "" {
+		acfg.Endpoints[0].Host = split.Traces.Endpoint
+		if split.Traces.APIKey != "" {
+			acfg.Endpoints[0].APIKey = split.Traces.APIKey
+		}
+	}
+	if split != nil && split.Enabled && split.Stats.Endpoint != "" {
+		// The trace-agent fans every configured Endpoints entry the same
+		// traces+stats payload; it has no notion of an APM-stats-only
+		// destination. So this cannot route stats independently of traces
+		// as split_endpoints.apm_stats might suggest — it can only add a
+		// second delivery target that receives both, i.e. dual-write. If
+		// split.Traces is also set, traces already go to split.Traces's
+		// endpoint and this adds the stats destination as a second, extra
+		// recipient of both; if it's not set, traces are dual-written here
+		// too, alongside stats, as a side effect of this limitation.
+		statsKey := cfg.API.Key
+		if split.Stats.APIKey != "" {
+			statsKey = split.Stats.APIKey
+		}
+		acfg.Endpoints = append(acfg.Endpoints, &traceconfig.Endpoint{
+			Host:   split.Stats.Endpoint,
+			APIKey: statsKey,
+		})
+	}
+
+	retryCfg := cfg.Retry
+	if retryCfg.Enabled && retryCfg.InitialInterval == 0 {
+		retryCfg = config.DefaultRetryConfig()
+	}
+
 	tracelog.SetLogger(&zaplogger{params.Logger})
 	agnt := agent.NewAgent(ctx, acfg)
 	exp := &traceExporter{
 		params:       params,
 		cfg:          cfg,
 		ctx:          ctx,
-		client:       client,
+		metricsSink:  metricsClient,
+		retryCfg:     retryCfg,
 		agent:        agnt,
+		telemetry:    telemetry,
 		onceMetadata: onceMetadata,
 		scrubber:     scrub.NewScrubber(),
 	}
@@ -92,6 +169,11 @@ func (exp *traceExporter) consumeTraces(
 	td ptrace.Traces,
 ) (err error) {
 	defer func() { err = exp.scrubber.Scrub(err) }()
+
+	rspans := td.ResourceSpans()
+	ctx, span := exp.telemetry.startConsumeTracesSpan(ctx, rspans.Len())
+	defer span.End()
+
 	if exp.cfg.HostMetadata.Enabled {
 		// start host metadata with resource attributes from
 		// the first payload.
@@ -103,21 +185,38 @@ func (exp *traceExporter) consumeTraces(
 			go metadata.Pusher(exp.ctx, exp.params, newMetadataConfigfromConfig(exp.cfg), attrs)
 		})
 	}
-	rspans := td.ResourceSpans()
 	hosts := make(map[string]struct{})
 	tags := make(map[string]struct{})
 	now := pcommon.NewTimestampFromTime(time.Now())
 	for i := 0; i < rspans.Len(); i++ {
 		rspan := rspans.At(i)
-		s := exp.agent.OTLPReceiver.ReceiveResourceSpans(rspan, http.Header{}, "otlp-exporter")
-		if s.Hostname != "" {
-			hosts[s.Hostname] = struct{}{}
+		start := time.Now()
+		hostname, spanTags, convErr := exp.receiveResourceSpans(rspan)
+		exp.telemetry.recordReceiveLatency(ctx, time.Since(start).Seconds())
+		if convErr != nil {
+			exp.params.Logger.Error("Dropping resource spans: failed to hand them to the trace agent", zap.Error(convErr))
+			exp.telemetry.addDroppedSpans(ctx, int64(countSpans(rspan)))
+			continue
+		}
+		exp.telemetry.addAcceptedSpans(ctx, int64(countSpans(rspan)))
+		if exp.telemetry.enabled {
+			// Record the serialized size of only the resource spans that were
+			// actually handed to the trace agent, so bytesForwarded stays
+			// consistent with acceptedSpans/droppedSpans during partial
+			// failures instead of double-counting spans we just dropped.
+			forwarded := ptrace.NewTraces()
+			rspan.CopyTo(forwarded.ResourceSpans().AppendEmpty())
+			exp.telemetry.addBytesForwarded(ctx, int64(ptrace.NewProtoMarshaler().TracesSize(forwarded)))
+		}
+		if hostname != "" {
+			hosts[hostname] = struct{}{}
 		} else {
-			for _, tag := range s.Tags {
+			for _, tag := range spanTags {
 				tags[tag] = struct{}{}
 			}
 		}
 	}
+	exp.telemetry.recordHostCardinality(span, len(hosts), len(tags))
 	series := make([]datadog.Metric, 0, len(hosts)+len(tags))
 	for host := range hosts {
 		series = append(series, metrics.DefaultMetrics("traces", host, uint64(now), exp.params.BuildInfo)...)
@@ -129,12 +228,135 @@ func (exp *traceExporter) consumeTraces(
 		}
 		series = append(series, ms...)
 	}
-	if err := exp.client.PostMetrics(series); err != nil {
+	if err := exp.postMetricsWithRetry(ctx, series); err != nil {
+		exp.telemetry.addMetricPostFailure(ctx)
 		exp.params.Logger.Error("Error posting hostname/tags series", zap.Error(err))
 	}
 	return nil
 }
 
+// receiveResourceSpans hands rspan to the trace agent's OTLP receiver,
+// recovering from a panic in that conversion so one malformed ResourceSpans
+// can't take down the whole consumeTraces call. This intentionally does not
+// retry with backoff the way postMetricsWithRetry does: ReceiveResourceSpans
+// is a synchronous, in-process hand-off into the agent's own sampler/writer
+// pipeline, not the network call that eventually delivers the span: that
+// HTTP delivery, and its own retry/backoff, is owned by the vendored
+// trace-agent's TraceWriter, not by this exporter.
+func (exp *traceExporter) receiveResourceSpans(rspan ptrace.ResourceSpans) (hostname string, tags []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic translating resource spans: %v", r)
+		}
+	}()
+	s := exp.agent.OTLPReceiver.ReceiveResourceSpans(rspan, http.Header{}, "otlp-exporter")
+	return s.Hostname, s.Tags, nil
+}
+
+// countSpans returns the total number of spans carried by a ResourceSpans,
+// across all of its instrumentation scopes.
+func countSpans(rspan ptrace.ResourceSpans) int {
+	total := 0
+	scopeSpans := rspan.ScopeSpans()
+	for i := 0; i < scopeSpans.Len(); i++ {
+		total += scopeSpans.At(i).Spans().Len()
+	}
+	return total
+}
+
+// postMetricsWithRetry posts series to exp.metricsSink, retrying transient
+// failures (429s, 5xxs, network errors) with exponential backoff. Permanent
+// failures (401/403 auth errors) are returned immediately without retrying.
+// A retryable error that reports a server-supplied Retry-After delay (see
+// retryAfterError) waits at least that long before the next attempt, even if
+// it's longer than the backoff's own next interval. Retries stop as soon as
+// exp.ctx is canceled, so exporter shutdown does not hang waiting on a dead
+// backend.
+func (exp *traceExporter) postMetricsWithRetry(ctx context.Context, series []datadog.Metric) error {
+	if !exp.retryCfg.Enabled {
+		return exp.metricsSink.PostMetrics(series)
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = exp.retryCfg.InitialInterval
+	bo.MaxInterval = exp.retryCfg.MaxInterval
+	bo.MaxElapsedTime = exp.retryCfg.MaxElapsedTime
+	bo.RandomizationFactor = exp.retryCfg.RandomizationFactor
+	bo.Reset()
+
+	for {
+		err := exp.metricsSink.PostMetrics(series)
+		if err == nil {
+			return nil
+		}
+		if isPermanentError(err) {
+			return err
+		}
+
+		wait := bo.NextBackOff()
+		if wait == backoff.Stop {
+			return err
+		}
+		if ra, ok := retryAfter(err); ok && ra > wait {
+			wait = ra
+		}
+
+		exp.telemetry.addMetricPostRetry(ctx)
+		exp.params.Logger.Warn("Retrying failed metrics post", zap.Error(err), zap.Duration("backoff", wait))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-exp.ctx.Done():
+			timer.Stop()
+			return exp.ctx.Err()
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// httpStatusError is implemented by an error that can report the HTTP
+// status code of the request that produced it. The vendored Datadog API
+// client used for running metrics (gopkg.in/zorkian/go-datadog-api.v2) has
+// no documented error type in this snapshot to assert against directly; if
+// its errors happen to satisfy this interface, classification below is
+// exact, and isPermanentError falls back to matching the status code's text
+// in the error message otherwise.
+type httpStatusError interface {
+	StatusCode() int
+}
+
+// retryAfterError is implemented by an error that can report a
+// server-supplied Retry-After delay (RFC 7231 section 7.1.3).
+type retryAfterError interface {
+	RetryAfter() (time.Duration, bool)
+}
+
+// retryAfter extracts a Retry-After delay from err, if it carries one.
+func retryAfter(err error) (time.Duration, bool) {
+	var rae retryAfterError
+	if errors.As(err, &rae) {
+		return rae.RetryAfter()
+	}
+	return 0, false
+}
+
+// isPermanentError reports whether err represents an authentication failure
+// (401/403) that retrying cannot fix, as opposed to a transient error such as
+// a 429, a 5xx, or a network timeout.
+func isPermanentError(err error) bool {
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		code := statusErr.StatusCode()
+		return code == http.StatusUnauthorized || code == http.StatusForbidden
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "401") || strings.Contains(msg, "403") ||
+		strings.Contains(msg, "Unauthorized") || strings.Contains(msg, "Forbidden")
+}
+
 func (exp *traceExporter) waitShutdown() {
 	exp.wg.Wait()
 }
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datadogexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+	"gopkg.in/zorkian/go-datadog-api.v2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/datadogexporter/config"
+)
+
+// fakeMetricsSink returns errs[i] from its i-th PostMetrics call, and nil
+// once errs is exhausted.
+type fakeMetricsSink struct {
+	mu       sync.Mutex
+	attempts int
+	errs     []error
+}
+
+func (f *fakeMetricsSink) PostMetrics(_ []datadog.Metric) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := f.attempts
+	f.attempts++
+	if i < len(f.errs) {
+		return f.errs[i]
+	}
+	return nil
+}
+
+func (f *fakeMetricsSink) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts
+}
+
+// fakeStatusError implements httpStatusError.
+type fakeStatusError struct {
+	code int
+}
+
+func (e *fakeStatusError) Error() string   { return "request failed" }
+func (e *fakeStatusError) StatusCode() int { return e.code }
+
+// fakeRetryAfterError implements retryAfterError, wrapping a plain error so
+// errors.As can still find it.
+type fakeRetryAfterError struct {
+	error
+	after time.Duration
+}
+
+func (e *fakeRetryAfterError) RetryAfter() (time.Duration, bool) { return e.after, true }
+
+func newTestTraceExporter(sink MetricsSink, retryCfg config.RetryConfig) *traceExporter {
+	return &traceExporter{
+		params:      component.ExporterCreateSettings{Logger: zap.NewNop()},
+		ctx:         context.Background(),
+		metricsSink: sink,
+		retryCfg:    retryCfg,
+		telemetry:   &exporterTelemetry{enabled: false},
+	}
+}
+
+func fastRetryConfig() config.RetryConfig {
+	return config.RetryConfig{
+		Enabled:             true,
+		InitialInterval:     time.Millisecond,
+		MaxInterval:         5 * time.Millisecond,
+		MaxElapsedTime:      time.Second,
+		RandomizationFactor: 0,
+	}
+}
+
+func TestIsPermanentError(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		permanent bool
+	}{
+		{"status 401", &fakeStatusError{code: 401}, true},
+		{"status 403", &fakeStatusError{code: 403}, true},
+		{"status 429", &fakeStatusError{code: 429}, false},
+		{"status 500", &fakeStatusError{code: 500}, false},
+		{"message mentions 401", errors.New("API returned 401"), true},
+		{"message mentions Unauthorized", errors.New("Unauthorized: bad key"), true},
+		{"message mentions Forbidden", errors.New("Forbidden"), true},
+		{"generic network error", errors.New("connection reset by peer"), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.permanent, isPermanentError(tc.err))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	t.Run("extracts a retryAfterError's delay", func(t *testing.T) {
+		d, ok := retryAfter(&fakeRetryAfterError{error: errors.New("429"), after: 2 * time.Second})
+		require.True(t, ok)
+		require.Equal(t, 2*time.Second, d)
+	})
+
+	t.Run("reports nothing for a plain error", func(t *testing.T) {
+		_, ok := retryAfter(errors.New("429"))
+		require.False(t, ok)
+	})
+}
+
+func TestPostMetricsWithRetrySucceedsAfterTransientFailures(t *testing.T) {
+	sink := &fakeMetricsSink{errs: []error{&fakeStatusError{code: 500}, &fakeStatusError{code: 503}}}
+	exp := newTestTraceExporter(sink, fastRetryConfig())
+
+	err := exp.postMetricsWithRetry(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, sink.callCount())
+}
+
+func TestPostMetricsWithRetryStopsOnPermanentError(t *testing.T) {
+	sink := &fakeMetricsSink{errs: []error{&fakeStatusError{code: 401}, nil}}
+	exp := newTestTraceExporter(sink, fastRetryConfig())
+
+	err := exp.postMetricsWithRetry(context.Background(), nil)
+	require.Error(t, err)
+	require.Equal(t, 1, sink.callCount())
+}
+
+func TestPostMetricsWithRetryHonorsRetryAfter(t *testing.T) {
+	retryAfterDelay := 100 * time.Millisecond
+	sink := &fakeMetricsSink{errs: []error{&fakeRetryAfterError{error: &fakeStatusError{code: 429}, after: retryAfterDelay}}}
+	exp := newTestTraceExporter(sink, fastRetryConfig())
+
+	start := time.Now()
+	err := exp.postMetricsWithRetry(context.Background(), nil)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, sink.callCount())
+	require.GreaterOrEqual(t, elapsed, retryAfterDelay, "retry should wait at least as long as the server-supplied Retry-After")
+}
+
+func TestPostMetricsWithRetryStopsOnContextCancellation(t *testing.T) {
+	sink := &fakeMetricsSink{errs: []error{&fakeStatusError{code: 500}, &fakeStatusError{code: 500}, &fakeStatusError{code: 500}}}
+	retryCfg := fastRetryConfig()
+	retryCfg.InitialInterval = time.Hour // never fires on its own within the test
+	retryCfg.MaxInterval = time.Hour
+	retryCfg.MaxElapsedTime = 0 // retry until canceled, rather than giving up on the first hour-long interval
+	exp := newTestTraceExporter(sink, retryCfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- exp.postMetricsWithRetry(ctx, nil)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("postMetricsWithRetry did not return promptly after ctx was canceled")
+	}
+}
+
+func TestPostMetricsWithRetryStopsOnExporterShutdown(t *testing.T) {
+	sink := &fakeMetricsSink{errs: []error{&fakeStatusError{code: 500}, &fakeStatusError{code: 500}}}
+	retryCfg := fastRetryConfig()
+	retryCfg.InitialInterval = time.Hour
+	retryCfg.MaxInterval = time.Hour
+	retryCfg.MaxElapsedTime = 0 // retry until canceled, rather than giving up on the first hour-long interval
+	exp := newTestTraceExporter(sink, retryCfg)
+
+	expCtx, expCancel := context.WithCancel(context.Background())
+	exp.ctx = expCtx
+
+	done := make(chan error, 1)
+	go func() {
+		done <- exp.postMetricsWithRetry(context.Background(), nil)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	expCancel()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("postMetricsWithRetry did not return promptly after exporter shutdown")
+	}
+}
@@ -35,13 +35,18 @@ func init() {
 // NewConfig creates a new stdin input config with default values
 func NewConfig(operatorID string) *Config {
 	return &Config{
-		InputConfig: helper.NewInputConfig(operatorID, "stdin"),
+		InputConfig:    helper.NewInputConfig(operatorID, "stdin"),
+		SplitterConfig: helper.NewSplitterConfig(),
 	}
 }
 
 // Config is the configuration of a stdin input operator.
 type Config struct {
-	helper.InputConfig `yaml:",inline"`
+	helper.InputConfig    `yaml:",inline"`
+	helper.SplitterConfig `yaml:",inline"`
+
+	helper.AttributerConfig `yaml:",inline"`
+	helper.ResourcerConfig  `yaml:",inline"`
 }
 
 // Build will build a stdin input operator.
@@ -51,18 +56,48 @@ func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
 		return nil, err
 	}
 
+	enc, err := helper.LookupEncoding(c.SplitterConfig.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	// flushAtEOF=true ensures any partially buffered multiline entry is
+	// emitted when stdin closes, rather than silently dropped.
+	splitFunc, err := c.SplitterConfig.Func(enc, true, int(c.SplitterConfig.Flusher.MaxLogSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build splitter: %w", err)
+	}
+
+	attributer, err := c.AttributerConfig.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	resourcer, err := c.ResourcerConfig.Build()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Input{
 		InputOperator: inputOperator,
 		stdin:         os.Stdin,
+		splitFunc:     splitFunc,
+		maxLogSize:    int(c.SplitterConfig.Flusher.MaxLogSize),
+		attributer:    attributer,
+		resourcer:     resourcer,
 	}, nil
 }
 
 // Input is an operator that reads input from stdin
 type Input struct {
 	helper.InputOperator
-	wg     sync.WaitGroup
-	cancel context.CancelFunc
-	stdin  *os.File
+	wg         sync.WaitGroup
+	cancel     context.CancelFunc
+	stdin      *os.File
+	splitFunc  bufio.SplitFunc
+	maxLogSize int
+	attributer helper.Attributer
+	resourcer  helper.Resourcer
 }
 
 // Start will start generating log entries.
@@ -81,6 +116,8 @@ func (g *Input) Start(_ operator.Persister) error {
 	}
 
 	scanner := bufio.NewScanner(g.stdin)
+	scanner.Buffer(make([]byte, 0, g.maxLogSize), g.maxLogSize)
+	scanner.Split(g.splitFunc)
 
 	g.wg.Add(1)
 	go func() {
@@ -100,15 +137,31 @@ func (g *Input) Start(_ operator.Persister) error {
 				return
 			}
 
-			e := entry.New()
-			e.Body = scanner.Text()
-			g.Write(ctx, e)
+			g.emit(ctx, scanner.Bytes())
 		}
 	}()
 
 	return nil
 }
 
+// emit builds an entry from a token produced by the splitter, applies any
+// configured attributes/resource, and writes it downstream.
+func (g *Input) emit(ctx context.Context, token []byte) {
+	e := entry.New()
+	e.Body = string(token)
+
+	if err := g.attributer.Attribute(e); err != nil {
+		g.Errorf("Failed to set attributes on entry", zap.Error(err))
+		return
+	}
+	if err := g.resourcer.Resource(e); err != nil {
+		g.Errorf("Failed to set resource on entry", zap.Error(err))
+		return
+	}
+
+	g.Write(ctx, e)
+}
+
 // Stop will stop generating logs.
 func (g *Input) Stop() error {
 	g.cancel()
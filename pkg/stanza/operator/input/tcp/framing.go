@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// FramingMode selects how the TCP input splits a byte stream into individual
+// log entries.
+type FramingMode string
+
+const (
+	// FramingNewline splits on "\n", stripping a trailing "\r" if present.
+	// This is the historical behavior and remains the default.
+	FramingNewline FramingMode = "newline"
+	// FramingNull splits on a NUL (0x00) byte.
+	FramingNull FramingMode = "null"
+	// FramingOctetCounted implements the RFC 6587 octet-counting transport,
+	// where each message is preceded by its length in bytes as ASCII
+	// decimal digits followed by a single space.
+	FramingOctetCounted FramingMode = "octet-counted"
+	// FramingCustom splits on matches of a user-supplied regular expression.
+	FramingCustom FramingMode = "custom"
+)
+
+// newSplitFunc builds the bufio.SplitFunc used to tokenize a connection's
+// byte stream according to mode. maxLogSize bounds both how large a single
+// token may be and, for octet-counted framing, the declared length prefix.
+func newSplitFunc(mode FramingMode, customRegex string, maxLogSize int) (bufio.SplitFunc, error) {
+	switch mode {
+	case "", FramingNewline:
+		return bufio.ScanLines, nil
+	case FramingNull:
+		return splitOnNull, nil
+	case FramingOctetCounted:
+		return newOctetCountedSplitFunc(maxLogSize), nil
+	case FramingCustom:
+		if customRegex == "" {
+			return nil, fmt.Errorf("missing required parameter 'framing_regex' for framing mode 'custom'")
+		}
+		re, err := regexp.Compile(customRegex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile 'framing_regex': %w", err)
+		}
+		return newRegexSplitFunc(re, maxLogSize), nil
+	default:
+		return nil, fmt.Errorf("invalid value for parameter 'framing': %q", mode)
+	}
+}
+
+// splitOnNull is a bufio.SplitFunc that tokenizes on NUL (0x00) bytes.
+func splitOnNull(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// newOctetCountedSplitFunc returns a bufio.SplitFunc implementing the RFC
+// 6587 octet-counting framing: "<length> <payload>", where length is the
+// payload's exact size in ASCII decimal digits.
+func newOctetCountedSplitFunc(maxLogSize int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		sp := bytes.IndexByte(data, ' ')
+		if sp < 0 {
+			// A length prefix is only ever a handful of digits; anything
+			// longer than that without a separating space is malformed
+			// rather than merely incomplete.
+			if len(data) > 10 {
+				return 0, nil, fmt.Errorf("malformed octet-counted frame: length prefix too long")
+			}
+			if atEOF {
+				return 0, nil, fmt.Errorf("malformed octet-counted frame: truncated length prefix")
+			}
+			return 0, nil, nil
+		}
+		if sp > 10 {
+			return 0, nil, fmt.Errorf("malformed octet-counted frame: length prefix too long")
+		}
+
+		length, err := strconv.Atoi(string(data[:sp]))
+		if err != nil || length < 0 {
+			return 0, nil, fmt.Errorf("malformed octet-counted frame: invalid length prefix %q", string(data[:sp]))
+		}
+		if length > maxLogSize {
+			return 0, nil, fmt.Errorf("malformed octet-counted frame: length %d exceeds max_log_size %d", length, maxLogSize)
+		}
+
+		frameEnd := sp + 1 + length
+		if len(data) < frameEnd {
+			if atEOF {
+				return 0, nil, fmt.Errorf("malformed octet-counted frame: truncated message, expected %d bytes", length)
+			}
+			return 0, nil, nil
+		}
+
+		return frameEnd, data[sp+1 : frameEnd], nil
+	}
+}
+
+// newRegexSplitFunc returns a bufio.SplitFunc that tokenizes on matches of
+// re, treating each match as a frame delimiter rather than part of either
+// adjacent token.
+func newRegexSplitFunc(re *regexp.Regexp, maxLogSize int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		if loc := re.FindIndex(data); loc != nil {
+			return loc[1], data[:loc[0]], nil
+		}
+
+		if len(data) >= maxLogSize {
+			return 0, nil, fmt.Errorf("custom framing: no delimiter match within max_log_size")
+		}
+		if atEOF {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
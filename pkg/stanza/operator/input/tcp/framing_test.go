@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOctetCountedSplitFunc(t *testing.T) {
+	cases := []struct {
+		name        string
+		data        string
+		atEOF       bool
+		maxLogSize  int
+		advance     int
+		token       string
+		expectErr   bool
+		errContains string
+	}{
+		{
+			name:       "single_complete_frame",
+			data:       "5 hello",
+			atEOF:      false,
+			maxLogSize: 1024,
+			advance:    7,
+			token:      "hello",
+		},
+		{
+			name:       "incomplete_frame_waits_for_more_data",
+			data:       "5 hel",
+			atEOF:      false,
+			maxLogSize: 1024,
+			advance:    0,
+			token:      "",
+		},
+		{
+			name:        "truncated_frame_at_eof_is_an_error",
+			data:        "5 hel",
+			atEOF:       true,
+			maxLogSize:  1024,
+			expectErr:   true,
+			errContains: "truncated message",
+		},
+		{
+			name:        "truncated_length_prefix_at_eof_is_an_error",
+			data:        "12345",
+			atEOF:       true,
+			maxLogSize:  1024,
+			expectErr:   true,
+			errContains: "truncated length prefix",
+		},
+		{
+			name:        "length_prefix_too_long_is_malformed",
+			data:        "12345678901 x",
+			atEOF:       false,
+			maxLogSize:  1024,
+			expectErr:   true,
+			errContains: "length prefix too long",
+		},
+		{
+			name:        "non_numeric_length_prefix_is_malformed",
+			data:        "abc hello",
+			atEOF:       false,
+			maxLogSize:  1024,
+			expectErr:   true,
+			errContains: "invalid length prefix",
+		},
+		{
+			name:        "length_over_max_log_size_is_rejected",
+			data:        "10 hi",
+			atEOF:       false,
+			maxLogSize:  4,
+			expectErr:   true,
+			errContains: "exceeds max_log_size",
+		},
+		{
+			name:       "no_space_yet_and_not_too_long_waits_for_more_data",
+			data:       "123",
+			atEOF:      false,
+			maxLogSize: 1024,
+			advance:    0,
+			token:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			split := newOctetCountedSplitFunc(tc.maxLogSize)
+			advance, token, err := split([]byte(tc.data), tc.atEOF)
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.advance, advance)
+			require.Equal(t, tc.token, string(token))
+		})
+	}
+}
+
+func TestOctetCountedSplitFuncAgainstScanner(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader("5 hello6 world!"))
+	scanner.Split(newOctetCountedSplitFunc(1024))
+
+	var tokens []string
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, []string{"hello", "world!"}, tokens)
+}
+
+func TestNewSplitFunc(t *testing.T) {
+	_, err := newSplitFunc(FramingCustom, "", 1024)
+	require.Error(t, err)
+
+	_, err = newSplitFunc(FramingCustom, "[", 1024)
+	require.Error(t, err)
+
+	_, err = newSplitFunc("bogus", "", 1024)
+	require.Error(t, err)
+
+	split, err := newSplitFunc(FramingOctetCounted, "", 1024)
+	require.NoError(t, err)
+	require.NotNil(t, split)
+}
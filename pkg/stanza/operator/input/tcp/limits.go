@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// connLimiter enforces MaxConnections and MaxConnectionsPerIP across the
+// accept loop, tracking active connections by remote IP.
+type connLimiter struct {
+	maxTotal int
+	maxPerIP int
+
+	mu         sync.Mutex
+	totalCount int
+	perIP      map[string]int
+}
+
+func newConnLimiter(maxTotal, maxPerIP int) *connLimiter {
+	return &connLimiter{
+		maxTotal: maxTotal,
+		maxPerIP: maxPerIP,
+		perIP:    make(map[string]int),
+	}
+}
+
+// acquire reserves a connection slot for ip, returning false if doing so
+// would exceed either configured cap.
+func (l *connLimiter) acquire(ip string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxTotal > 0 && l.totalCount >= l.maxTotal {
+		return false
+	}
+	if l.maxPerIP > 0 && l.perIP[ip] >= l.maxPerIP {
+		return false
+	}
+
+	l.totalCount++
+	l.perIP[ip]++
+	return true
+}
+
+func (l *connLimiter) release(ip string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.totalCount--
+	l.perIP[ip]--
+	if l.perIP[ip] <= 0 {
+		delete(l.perIP, ip)
+	}
+}
+
+// remoteIP returns the IP portion of conn.RemoteAddr(), or the full string
+// representation if it isn't a *net.TCPAddr.
+func remoteIP(conn net.Conn) string {
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP.String()
+	}
+	return conn.RemoteAddr().String()
+}
+
+// rateLimitedConn wraps a net.Conn's Read with a token-bucket byte rate
+// limiter, so a single slow or abusive sender cannot monopolize the
+// operator's read bandwidth.
+type rateLimitedConn struct {
+	net.Conn
+	ctx     context.Context
+	limiter *rate.Limiter
+	metrics *connectionMetrics
+}
+
+func newRateLimitedConn(ctx context.Context, conn net.Conn, bytesPerSecond int, metrics *connectionMetrics) net.Conn {
+	if bytesPerSecond <= 0 {
+		return conn
+	}
+	// The burst must be able to absorb a single read of up to the
+	// configured rate even though the underlying reader may hand back
+	// larger chunks than that in one call; floor it well above typical
+	// socket read sizes so WaitN never rejects a read outright.
+	burst := bytesPerSecond
+	if burst < minRateLimiterBurst {
+		burst = minRateLimiterBurst
+	}
+	return &rateLimitedConn{
+		Conn:    conn,
+		ctx:     ctx,
+		limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), burst),
+		metrics: metrics,
+	}
+}
+
+// minRateLimiterBurst bounds the token bucket's burst size so a single
+// socket read larger than the configured rate doesn't fail WaitN outright.
+const minRateLimiterBurst = 64 * 1024
+
+func (c *rateLimitedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.metrics.addBytesRead(c.ctx, int64(n))
+		// WaitN rejects outright any request larger than the limiter's
+		// burst, but a single underlying Read can return more bytes than
+		// that (e.g. under TCP window scaling with a large receive
+		// buffer). Charge the bucket in burst-sized chunks instead of in
+		// one call, so a large Read is throttled rather than killed.
+		remaining := n
+		burst := c.limiter.Burst()
+		for remaining > 0 {
+			chunk := remaining
+			if chunk > burst {
+				chunk = burst
+			}
+			if werr := c.limiter.WaitN(c.ctx, chunk); werr != nil {
+				return n, werr
+			}
+			remaining -= chunk
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnLimiterAcquireRelease(t *testing.T) {
+	l := newConnLimiter(2, 1)
+
+	require.True(t, l.acquire("10.0.0.1"))
+	require.False(t, l.acquire("10.0.0.1"), "per-IP cap of 1 should reject a second connection from the same IP")
+	require.True(t, l.acquire("10.0.0.2"))
+	require.False(t, l.acquire("10.0.0.3"), "total cap of 2 should reject a third connection regardless of IP")
+
+	l.release("10.0.0.1")
+	require.True(t, l.acquire("10.0.0.3"), "releasing a slot should allow a new connection in")
+}
+
+func TestConnLimiterUnlimited(t *testing.T) {
+	l := newConnLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		require.True(t, l.acquire("10.0.0.1"))
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+	reads [][]byte
+	i     int
+}
+
+func (f *fakeConn) Read(p []byte) (int, error) {
+	if f.i >= len(f.reads) {
+		return 0, net.ErrClosed
+	}
+	n := copy(p, f.reads[f.i])
+	f.i++
+	return n, nil
+}
+
+func TestRateLimitedConnClampsToBurst(t *testing.T) {
+	metrics, err := newConnectionMetrics()
+	require.NoError(t, err)
+
+	// A single underlying Read larger than the configured rate (and thus
+	// larger than the limiter's burst) must be throttled, not rejected
+	// outright with a "burst exceeded" error.
+	big := make([]byte, 256*1024)
+	conn := newRateLimitedConn(context.Background(), &fakeConn{reads: [][]byte{big}}, 192*1024, metrics)
+
+	buf := make([]byte, len(big))
+	n, rerr := conn.Read(buf)
+	require.NoError(t, rerr)
+	require.Equal(t, len(big), n)
+}
+
+func TestRateLimitedConnThrottles(t *testing.T) {
+	metrics, err := newConnectionMetrics()
+	require.NoError(t, err)
+
+	chunk := make([]byte, 64*1024)
+	conn := newRateLimitedConn(context.Background(), &fakeConn{reads: [][]byte{chunk, chunk}}, 64*1024, metrics)
+
+	buf := make([]byte, len(chunk))
+	start := time.Now()
+	_, rerr := conn.Read(buf)
+	require.NoError(t, rerr)
+	_, rerr = conn.Read(buf)
+	require.NoError(t, rerr)
+	// The second read's worth of bytes exceeds the per-second rate, so it
+	// must have been delayed rather than let through immediately.
+	require.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestNewRateLimitedConnDisabled(t *testing.T) {
+	metrics, err := newConnectionMetrics()
+	require.NoError(t, err)
+
+	var c net.Conn = &fakeConn{}
+	require.Same(t, c, newRateLimitedConn(context.Background(), c, 0, metrics))
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+// connectionMetrics exposes the TCP input's accept-loop behavior as
+// OpenTelemetry instruments, so operators can alarm on connection churn,
+// rejection, or parse failures from untrusted networks without scraping logs.
+type connectionMetrics struct {
+	accepted    metric.Int64Counter
+	rejected    metric.Int64Counter
+	active      metric.Int64UpDownCounter
+	bytesRead   metric.Int64Counter
+	parseErrors metric.Int64Counter
+}
+
+func newConnectionMetrics() (*connectionMetrics, error) {
+	meter := otel.Meter(meterName)
+
+	accepted, err := meter.Int64Counter(
+		"otelcol_stanza_tcp_connections_accepted",
+		metric.WithDescription("Number of TCP connections accepted"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create accepted connections counter: %w", err)
+	}
+
+	rejected, err := meter.Int64Counter(
+		"otelcol_stanza_tcp_connections_rejected",
+		metric.WithDescription("Number of TCP connections rejected due to max_connections or max_connections_per_ip"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rejected connections counter: %w", err)
+	}
+
+	active, err := meter.Int64UpDownCounter(
+		"otelcol_stanza_tcp_connections_active",
+		metric.WithDescription("Number of TCP connections currently open"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create active connections counter: %w", err)
+	}
+
+	bytesRead, err := meter.Int64Counter(
+		"otelcol_stanza_tcp_bytes_read",
+		metric.WithDescription("Number of bytes read from accepted TCP connections"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bytes read counter: %w", err)
+	}
+
+	parseErrors, err := meter.Int64Counter(
+		"otelcol_stanza_tcp_parse_errors",
+		metric.WithDescription("Number of connections dropped due to a PROXY protocol, framing, or TLS handshake error"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parse errors counter: %w", err)
+	}
+
+	return &connectionMetrics{
+		accepted:    accepted,
+		rejected:    rejected,
+		active:      active,
+		bytesRead:   bytesRead,
+		parseErrors: parseErrors,
+	}, nil
+}
+
+func (m *connectionMetrics) addAccepted(ctx context.Context)           { m.accepted.Add(ctx, 1) }
+func (m *connectionMetrics) addRejected(ctx context.Context)           { m.rejected.Add(ctx, 1) }
+func (m *connectionMetrics) addActive(ctx context.Context, n int64)    { m.active.Add(ctx, n) }
+func (m *connectionMetrics) addBytesRead(ctx context.Context, n int64) { m.bytesRead.Add(ctx, n) }
+func (m *connectionMetrics) addParseError(ctx context.Context)         { m.parseErrors.Add(ctx, 1) }
@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspRequestTimeout bounds how long verifyOCSPStaple will wait on the
+// issuer's OCSP responder. It runs synchronously inside the TLS handshake's
+// VerifyPeerCertificate callback, so an unbounded wait here would let a
+// slow or unreachable responder hang the handshake goroutine indefinitely.
+const ocspRequestTimeout = 5 * time.Second
+
+var ocspHTTPClient = &http.Client{Timeout: ocspRequestTimeout}
+
+// crlVerifier rejects client certificates whose serial number appears in any
+// of a configured set of CRL files. Each file is parsed once and re-parsed
+// only when its mtime changes, so a long-running listener picks up revised
+// CRLs without a restart.
+type crlVerifier struct {
+	paths []string
+
+	mu      sync.Mutex
+	entries map[string]*cachedCRL
+}
+
+type cachedCRL struct {
+	modTime time.Time
+	revoked map[string]struct{}
+}
+
+func newCRLVerifier(paths []string) (*crlVerifier, error) {
+	v := &crlVerifier{paths: paths, entries: make(map[string]*cachedCRL)}
+	for _, path := range paths {
+		if _, err := v.load(path); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (v *crlVerifier) load(path string) (*cachedCRL, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat CRL file %q: %w", path, err)
+	}
+
+	v.mu.Lock()
+	cached, ok := v.entries[path]
+	v.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) {
+		return cached, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRL file %q: %w", path, err)
+	}
+	list, err := x509.ParseCRL(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CRL file %q: %w", path, err)
+	}
+
+	revoked := make(map[string]struct{}, len(list.TBSCertList.RevokedCertificates))
+	for _, rc := range list.TBSCertList.RevokedCertificates {
+		revoked[rc.SerialNumber.String()] = struct{}{}
+	}
+	cached = &cachedCRL{modTime: info.ModTime(), revoked: revoked}
+
+	v.mu.Lock()
+	v.entries[path] = cached
+	v.mu.Unlock()
+	return cached, nil
+}
+
+// verifyPeerCertificate implements tls.Config.VerifyPeerCertificate. It
+// reloads any CRL whose file has changed since it was last parsed, then
+// rejects the handshake if any presented certificate's serial number is
+// revoked under any configured CRL.
+func (v *crlVerifier) verifyPeerCertificate(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, path := range v.paths {
+		cached, err := v.load(path)
+		if err != nil {
+			return err
+		}
+		for _, chain := range verifiedChains {
+			for _, cert := range chain {
+				if _, revoked := cached.revoked[cert.SerialNumber.String()]; revoked {
+					return fmt.Errorf("certificate with serial %s is revoked", cert.SerialNumber.String())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// verifyOCSPStaple checks the leaf certificate in each verified chain against
+// its issuer's OCSP responder, rejecting the handshake if the responder
+// reports the certificate as revoked. Responder errors (unreachable, no
+// responder configured) are treated as inconclusive rather than fatal, since
+// OCSP availability is not guaranteed for client certificates.
+func verifyOCSPStaple(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		if len(chain) < 2 {
+			continue
+		}
+		leaf, issuer := chain[0], chain[1]
+		if len(leaf.OCSPServer) == 0 {
+			continue
+		}
+
+		req, err := ocsp.CreateRequest(leaf, issuer, nil)
+		if err != nil {
+			continue
+		}
+		httpResp, err := ocspHTTPClient.Post(leaf.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(httpResp.Body)
+		httpResp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		resp, err := ocsp.ParseResponse(body, issuer)
+		if err != nil {
+			continue
+		}
+		if resp.Status == ocsp.Revoked {
+			return fmt.Errorf("certificate with serial %s is revoked per OCSP", leaf.SerialNumber.String())
+		}
+	}
+	return nil
+}
+
+// chainVerifiers combines two tls.Config.VerifyPeerCertificate callbacks,
+// running next only if prev is nil or succeeds. Either argument may be nil.
+func chainVerifiers(prev, next func([][]byte, [][]*x509.Certificate) error) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if prev != nil {
+			if err := prev(rawCerts, verifiedChains); err != nil {
+				return err
+			}
+		}
+		if next != nil {
+			return next(rawCerts, verifiedChains)
+		}
+		return nil
+	}
+}
+
+// peerTLSAttributes derives entry attributes describing the verified client
+// certificate presented during an mTLS handshake.
+func peerTLSAttributes(state tls.ConnectionState) map[string]interface{} {
+	if len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := state.PeerCertificates[0]
+
+	attrs := map[string]interface{}{
+		"net.peer.tls.subject":            cert.Subject.String(),
+		"net.peer.tls.issuer":             cert.Issuer.String(),
+		"net.peer.tls.fingerprint_sha256": fmt.Sprintf("%x", sha256.Sum256(cert.Raw)),
+	}
+	if len(cert.DNSNames) > 0 {
+		attrs["net.peer.tls.san.dns"] = cert.DNSNames
+	}
+	if len(cert.URIs) > 0 {
+		uris := make([]string, len(cert.URIs))
+		for i, u := range cert.URIs {
+			uris[i] = u.String()
+		}
+		attrs["net.peer.tls.san.uri"] = uris
+	}
+	return attrs
+}
@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func testLeafCertAndKey(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+	block, _ := pem.Decode([]byte(testTLSCertificate))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	keyBlock, _ := pem.Decode([]byte(testTLSPrivateKey))
+	require.NotNil(t, keyBlock)
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	require.NoError(t, err)
+	return cert, key.(*rsa.PrivateKey)
+}
+
+func writeTestCRL(t *testing.T, path string, cert *x509.Certificate, key *rsa.PrivateKey, revoked []pkix.RevokedCertificate) {
+	t.Helper()
+	der, err := cert.CreateCRL(nil, key, revoked, time.Now(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0o600))
+}
+
+func TestCRLVerifier(t *testing.T) {
+	cert, key := testLeafCertAndKey(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.crl")
+
+	t.Run("not_revoked", func(t *testing.T) {
+		writeTestCRL(t, path, cert, key, nil)
+		v, err := newCRLVerifier([]string{path})
+		require.NoError(t, err)
+		require.NoError(t, v.verifyPeerCertificate(nil, [][]*x509.Certificate{{cert}}))
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		writeTestCRL(t, path, cert, key, []pkix.RevokedCertificate{
+			{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()},
+		})
+		v, err := newCRLVerifier([]string{path})
+		require.NoError(t, err)
+		err = v.verifyPeerCertificate(nil, [][]*x509.Certificate{{cert}})
+		require.ErrorContains(t, err, "is revoked")
+	})
+
+	t.Run("reloads_on_mtime_change", func(t *testing.T) {
+		writeTestCRL(t, path, cert, key, nil)
+		v, err := newCRLVerifier([]string{path})
+		require.NoError(t, err)
+		require.NoError(t, v.verifyPeerCertificate(nil, [][]*x509.Certificate{{cert}}))
+
+		// Advance mtime explicitly: consecutive writes within the same
+		// timestamp granularity must not be mistaken for "unchanged".
+		writeTestCRL(t, path, cert, key, []pkix.RevokedCertificate{
+			{SerialNumber: cert.SerialNumber, RevocationTime: time.Now()},
+		})
+		future := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, future, future))
+
+		err = v.verifyPeerCertificate(nil, [][]*x509.Certificate{{cert}})
+		require.ErrorContains(t, err, "is revoked")
+	})
+
+	t.Run("missing_file_errors", func(t *testing.T) {
+		_, err := newCRLVerifier([]string{filepath.Join(dir, "does-not-exist.crl")})
+		require.Error(t, err)
+	})
+
+	t.Run("malformed_file_errors", func(t *testing.T) {
+		badPath := filepath.Join(dir, "bad.crl")
+		require.NoError(t, os.WriteFile(badPath, []byte("not a crl"), 0o600))
+		_, err := newCRLVerifier([]string{badPath})
+		require.Error(t, err)
+	})
+}
+
+func TestVerifyOCSPStaple(t *testing.T) {
+	leaf, key := testLeafCertAndKey(t)
+	// leaf is self-signed, so it is its own issuer for the purposes of this
+	// test: verifyOCSPStaple only looks at chain[0] (leaf) and chain[1]
+	// (issuer), it never checks that the issuer actually signed the leaf.
+	issuer := leaf
+
+	t.Run("no_ocsp_server_is_skipped", func(t *testing.T) {
+		require.NoError(t, verifyOCSPStaple(nil, [][]*x509.Certificate{{leaf, issuer}}))
+	})
+
+	t.Run("single_certificate_chain_is_skipped", func(t *testing.T) {
+		require.NoError(t, verifyOCSPStaple(nil, [][]*x509.Certificate{{leaf}}))
+	})
+
+	t.Run("unreachable_responder_is_inconclusive_not_fatal", func(t *testing.T) {
+		withLeaf := *leaf
+		withLeaf.OCSPServer = []string{"http://127.0.0.1:0"}
+		require.NoError(t, verifyOCSPStaple(nil, [][]*x509.Certificate{{&withLeaf, issuer}}))
+	})
+
+	t.Run("responder_reports_revoked", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			respTmpl := ocsp.Response{
+				Status:       ocsp.Revoked,
+				SerialNumber: leaf.SerialNumber,
+				ThisUpdate:   time.Now(),
+				NextUpdate:   time.Now().Add(time.Hour),
+			}
+			der, err := ocsp.CreateResponse(issuer, issuer, respTmpl, key)
+			require.NoError(t, err)
+			w.Write(der)
+		}))
+		defer srv.Close()
+
+		withLeaf := *leaf
+		withLeaf.OCSPServer = []string{srv.URL}
+		err := verifyOCSPStaple(nil, [][]*x509.Certificate{{&withLeaf, issuer}})
+		require.ErrorContains(t, err, "revoked per OCSP")
+	})
+
+	t.Run("responder_reports_good", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			respTmpl := ocsp.Response{
+				Status:       ocsp.Good,
+				SerialNumber: leaf.SerialNumber,
+				ThisUpdate:   time.Now(),
+				NextUpdate:   time.Now().Add(time.Hour),
+			}
+			der, err := ocsp.CreateResponse(issuer, issuer, respTmpl, key)
+			require.NoError(t, err)
+			w.Write(der)
+		}))
+		defer srv.Close()
+
+		withLeaf := *leaf
+		withLeaf.OCSPServer = []string{srv.URL}
+		require.NoError(t, verifyOCSPStaple(nil, [][]*x509.Certificate{{&withLeaf, issuer}}))
+	})
+}
+
+var errTest = errors.New("verifier failed")
+
+func TestChainVerifiers(t *testing.T) {
+	calls := 0
+	ok := func([][]byte, [][]*x509.Certificate) error { calls++; return nil }
+	failing := func([][]byte, [][]*x509.Certificate) error { calls++; return errTest }
+
+	require.NoError(t, chainVerifiers(nil, ok)(nil, nil))
+	require.Equal(t, 1, calls)
+
+	calls = 0
+	require.ErrorIs(t, chainVerifiers(failing, ok)(nil, nil), errTest)
+	require.Equal(t, 1, calls, "next must not run once prev fails")
+
+	calls = 0
+	require.NoError(t, chainVerifiers(ok, ok)(nil, nil))
+	require.Equal(t, 2, calls)
+
+	calls = 0
+	require.NoError(t, chainVerifiers(ok, nil)(nil, nil), "a nil next must not panic")
+	require.Equal(t, 1, calls)
+
+	calls = 0
+	require.NoError(t, chainVerifiers(nil, nil)(nil, nil))
+	require.Equal(t, 0, calls)
+}
+
+func TestPeerTLSAttributes(t *testing.T) {
+	cert, _ := testLeafCertAndKey(t)
+	attrs := peerTLSAttributes(tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}})
+	require.Equal(t, cert.Subject.String(), attrs["net.peer.tls.subject"])
+	require.Equal(t, cert.Issuer.String(), attrs["net.peer.tls.issuer"])
+	require.NotEmpty(t, attrs["net.peer.tls.fingerprint_sha256"])
+
+	require.Nil(t, peerTLSAttributes(tls.ConnectionState{}))
+}
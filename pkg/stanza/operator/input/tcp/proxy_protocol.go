@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ProxyProtocolMode controls whether and how the TCP input expects a PROXY
+// protocol (v1/v2) header in front of each connection.
+type ProxyProtocolMode string
+
+const (
+	// ProxyProtocolOff never attempts to read a PROXY protocol header.
+	ProxyProtocolOff ProxyProtocolMode = "off"
+	// ProxyProtocolV1 accepts only the human-readable v1 header.
+	ProxyProtocolV1 ProxyProtocolMode = "v1"
+	// ProxyProtocolV2 accepts only the binary v2 header.
+	ProxyProtocolV2 ProxyProtocolMode = "v2"
+	// ProxyProtocolAny accepts either version, falling back to treating the
+	// connection as a plain, unproxied connection if neither is present.
+	ProxyProtocolAny ProxyProtocolMode = "any"
+	// ProxyProtocolRequire behaves like ProxyProtocolAny, but rejects the
+	// connection outright when no valid header is present.
+	ProxyProtocolRequire ProxyProtocolMode = "require"
+)
+
+// proxyProtocolV2Signature is the fixed 12-byte prefix of every PROXY
+// protocol v2 header. See https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt.
+var proxyProtocolV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+// proxyHeader carries the original client address as reported by a PROXY
+// protocol header, replacing values the operator would otherwise have read
+// from conn.RemoteAddr()/conn.LocalAddr().
+type proxyHeader struct {
+	SourceIP   string
+	SourcePort int
+	DestIP     string
+	DestPort   int
+}
+
+// bufferedConn wraps a net.Conn whose first bytes have already been Peek'd
+// into a *bufio.Reader while parsing a PROXY protocol header, so that any
+// bytes buffered past the header (e.g. a TLS ClientHello arriving in the same
+// packet) are not lost to a later direct Read on the underlying conn.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// readProxyHeader consumes a PROXY protocol header from conn, if t.proxyProtocol
+// calls for one, and returns the conn to use for all further reads (which may
+// wrap conn to preserve buffered bytes) along with the parsed header, if any.
+// A nil header with a nil error means no header was present, which is only
+// possible outside of ProxyProtocolRequire.
+func (t *Input) readProxyHeader(conn net.Conn) (net.Conn, *proxyHeader, error) {
+	if t.proxyProtocol == "" || t.proxyProtocol == ProxyProtocolOff {
+		return conn, nil, nil
+	}
+
+	br := bufio.NewReaderSize(conn, 256)
+	wrapped := &bufferedConn{Conn: conn, r: br}
+
+	tryV2 := t.proxyProtocol == ProxyProtocolV2 || t.proxyProtocol == ProxyProtocolAny || t.proxyProtocol == ProxyProtocolRequire
+	if tryV2 {
+		if sig, err := br.Peek(len(proxyProtocolV2Signature)); err == nil && bytes.Equal(sig, proxyProtocolV2Signature) {
+			hdr, err := parseProxyV2(br)
+			return wrapped, hdr, err
+		}
+	}
+
+	tryV1 := t.proxyProtocol == ProxyProtocolV1 || t.proxyProtocol == ProxyProtocolAny || t.proxyProtocol == ProxyProtocolRequire
+	if tryV1 {
+		if prefix, err := br.Peek(6); err == nil && string(prefix) == "PROXY " {
+			hdr, err := parseProxyV1(br)
+			return wrapped, hdr, err
+		}
+	}
+
+	if t.proxyProtocol == ProxyProtocolRequire {
+		return wrapped, nil, fmt.Errorf("connection did not present a PROXY protocol header")
+	}
+	return wrapped, nil, nil
+}
+
+// parseProxyV1 parses a PROXY protocol v1 text header, e.g.
+// "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n" or "PROXY UNKNOWN\r\n".
+func parseProxyV1(br *bufio.Reader) (*proxyHeader, error) {
+	// Read byte-by-byte directly from br, rather than br.ReadString (which
+	// would buffer an unbounded amount of data if a client never sends a
+	// trailing \n), so a missing delimiter is rejected in bounded time and
+	// memory. This also leaves any bytes past the header line sitting in
+	// br's own buffer for the caller to read, unlike wrapping br in a second
+	// bufio.Reader, which would read ahead and silently swallow them.
+	var buf bytes.Buffer
+	for {
+		if buf.Len() >= 107 {
+			return nil, fmt.Errorf("malformed PROXY v1 header: line too long")
+		}
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("malformed PROXY v1 header: %w", err)
+		}
+		buf.WriteByte(b)
+		if b == '\n' {
+			break
+		}
+	}
+	line := buf.String()
+
+	fields := strings.Fields(strings.TrimRight(line, "\r\n"))
+	if len(fields) < 2 || fields[0] != "PROXY" {
+		return nil, fmt.Errorf("malformed PROXY v1 header: %q", line)
+	}
+
+	switch fields[1] {
+	case "UNKNOWN":
+		return &proxyHeader{}, nil
+	case "TCP4", "TCP6":
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("malformed PROXY v1 header: %q", line)
+		}
+		srcPort, err := strconv.Atoi(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("malformed PROXY v1 header: invalid source port: %w", err)
+		}
+		dstPort, err := strconv.Atoi(fields[5])
+		if err != nil {
+			return nil, fmt.Errorf("malformed PROXY v1 header: invalid destination port: %w", err)
+		}
+		return &proxyHeader{
+			SourceIP:   fields[2],
+			SourcePort: srcPort,
+			DestIP:     fields[3],
+			DestPort:   dstPort,
+		}, nil
+	default:
+		return nil, fmt.Errorf("malformed PROXY v1 header: unsupported protocol %q", fields[1])
+	}
+}
+
+// parseProxyV2 parses a PROXY protocol v2 binary header. Only the fixed
+// address block is decoded; any trailing TLVs are read (so they don't leak
+// into the log stream) but otherwise ignored.
+func parseProxyV2(br *bufio.Reader) (*proxyHeader, error) {
+	fixed := make([]byte, 16)
+	if _, err := io.ReadFull(br, fixed); err != nil {
+		return nil, fmt.Errorf("malformed PROXY v2 header: %w", err)
+	}
+
+	verCmd := fixed[12]
+	if verCmd>>4 != 2 {
+		return nil, fmt.Errorf("malformed PROXY v2 header: unsupported version %d", verCmd>>4)
+	}
+	cmd := verCmd & 0x0F
+	family := fixed[13] >> 4
+	length := binary.BigEndian.Uint16(fixed[14:16])
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(br, body); err != nil {
+		return nil, fmt.Errorf("malformed PROXY v2 header: %w", err)
+	}
+
+	if cmd == 0x0 {
+		// LOCAL command: a health check from the proxy itself, not a
+		// proxied client. There is no client address to report.
+		return &proxyHeader{}, nil
+	}
+
+	switch family {
+	case 0x1: // AF_INET
+		if len(body) < 12 {
+			return nil, fmt.Errorf("malformed PROXY v2 header: short IPv4 address block")
+		}
+		return &proxyHeader{
+			SourceIP:   net.IP(body[0:4]).String(),
+			DestIP:     net.IP(body[4:8]).String(),
+			SourcePort: int(binary.BigEndian.Uint16(body[8:10])),
+			DestPort:   int(binary.BigEndian.Uint16(body[10:12])),
+		}, nil
+	case 0x2: // AF_INET6
+		if len(body) < 36 {
+			return nil, fmt.Errorf("malformed PROXY v2 header: short IPv6 address block")
+		}
+		return &proxyHeader{
+			SourceIP:   net.IP(body[0:16]).String(),
+			DestIP:     net.IP(body[16:32]).String(),
+			SourcePort: int(binary.BigEndian.Uint16(body[32:34])),
+			DestPort:   int(binary.BigEndian.Uint16(body[34:36])),
+		}, nil
+	default:
+		// AF_UNSPEC or AF_UNIX: no routable address to extract.
+		return &proxyHeader{}, nil
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProxyV1(t *testing.T) {
+	cases := []struct {
+		name        string
+		line        string
+		expectErr   bool
+		errContains string
+		header      *proxyHeader
+	}{
+		{
+			name: "tcp4",
+			line: "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n",
+			header: &proxyHeader{
+				SourceIP: "192.168.0.1", SourcePort: 56324,
+				DestIP: "192.168.0.11", DestPort: 443,
+			},
+		},
+		{
+			name:   "unknown",
+			line:   "PROXY UNKNOWN\r\n",
+			header: &proxyHeader{},
+		},
+		{
+			name:        "missing_proxy_keyword",
+			line:        "GET / HTTP/1.1\r\n",
+			expectErr:   true,
+			errContains: "malformed PROXY v1 header",
+		},
+		{
+			name:        "truncated_address_fields",
+			line:        "PROXY TCP4 192.168.0.1\r\n",
+			expectErr:   true,
+			errContains: "malformed PROXY v1 header",
+		},
+		{
+			name:        "non_numeric_port",
+			line:        "PROXY TCP4 192.168.0.1 192.168.0.11 notaport 443\r\n",
+			expectErr:   true,
+			errContains: "invalid source port",
+		},
+		{
+			name:        "unsupported_protocol",
+			line:        "PROXY UNIX /a /b\r\n",
+			expectErr:   true,
+			errContains: "unsupported protocol",
+		},
+		{
+			name:        "line_too_long",
+			line:        "PROXY TCP4 " + strings.Repeat("1", 120) + " 192.168.0.11 56324 443\r\n",
+			expectErr:   true,
+			errContains: "line too long",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			hdr, err := parseProxyV1(bufio.NewReader(strings.NewReader(tc.line)))
+			if tc.expectErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.header, hdr)
+		})
+	}
+}
+
+func TestParseProxyV1Truncated(t *testing.T) {
+	_, err := parseProxyV1(bufio.NewReader(strings.NewReader("PROXY TCP4 192.168.0.1")))
+	require.Error(t, err)
+}
+
+// infiniteReader never errors and never emits '\n', simulating a client that
+// holds the connection open and streams bytes without ever completing the
+// PROXY v1 line.
+type infiniteReader struct{}
+
+func (infiniteReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	return len(p), nil
+}
+
+func TestParseProxyV1PreservesTrailingBytes(t *testing.T) {
+	br := bufio.NewReader(strings.NewReader("PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\nEXTRADATA"))
+	hdr, err := parseProxyV1(br)
+	require.NoError(t, err)
+	require.Equal(t, &proxyHeader{SourceIP: "192.168.0.1", SourcePort: 56324, DestIP: "192.168.0.11", DestPort: 443}, hdr)
+
+	rest, err := br.ReadString(0)
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, "EXTRADATA", rest)
+}
+
+func TestParseProxyV1NoDelimiterEver(t *testing.T) {
+	done := make(chan error, 1)
+	go func() {
+		_, err := parseProxyV1(bufio.NewReader(infiniteReader{}))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "line too long")
+	case <-time.After(time.Second):
+		t.Fatal("parseProxyV1 did not return in bounded time/memory when no delimiter was ever sent")
+	}
+}
+
+// buildProxyV2 assembles a minimal, well-formed PROXY protocol v2 header for
+// the given address family and address block.
+func buildProxyV2(t *testing.T, family byte, body []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.Write(proxyProtocolV2Signature)
+	buf.WriteByte(0x21) // version 2, command PROXY
+	buf.WriteByte(family << 4)
+	var length [2]byte
+	binary.BigEndian.PutUint16(length[:], uint16(len(body)))
+	buf.Write(length[:])
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+func TestParseProxyV2(t *testing.T) {
+	t.Run("ipv4", func(t *testing.T) {
+		body := make([]byte, 12)
+		copy(body[0:4], net.ParseIP("10.0.0.1").To4())
+		copy(body[4:8], net.ParseIP("10.0.0.2").To4())
+		binary.BigEndian.PutUint16(body[8:10], 1234)
+		binary.BigEndian.PutUint16(body[10:12], 443)
+
+		raw := buildProxyV2(t, 0x1, body)
+		hdr, err := parseProxyV2(bufio.NewReader(bytes.NewReader(raw)))
+		require.NoError(t, err)
+		require.Equal(t, &proxyHeader{
+			SourceIP: "10.0.0.1", SourcePort: 1234,
+			DestIP: "10.0.0.2", DestPort: 443,
+		}, hdr)
+	})
+
+	t.Run("ipv6", func(t *testing.T) {
+		body := make([]byte, 36)
+		copy(body[0:16], net.ParseIP("::1").To16())
+		copy(body[16:32], net.ParseIP("::2").To16())
+		binary.BigEndian.PutUint16(body[32:34], 1234)
+		binary.BigEndian.PutUint16(body[34:36], 443)
+
+		raw := buildProxyV2(t, 0x2, body)
+		hdr, err := parseProxyV2(bufio.NewReader(bytes.NewReader(raw)))
+		require.NoError(t, err)
+		require.Equal(t, "::1", hdr.SourceIP)
+		require.Equal(t, "::2", hdr.DestIP)
+	})
+
+	t.Run("local_command_has_no_address", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.Write(proxyProtocolV2Signature)
+		buf.WriteByte(0x20) // version 2, command LOCAL
+		buf.WriteByte(0x00)
+		buf.Write([]byte{0x00, 0x00})
+
+		hdr, err := parseProxyV2(bufio.NewReader(bytes.NewReader(buf.Bytes())))
+		require.NoError(t, err)
+		require.Equal(t, &proxyHeader{}, hdr)
+	})
+
+	t.Run("unsupported_version", func(t *testing.T) {
+		fixed := make([]byte, 16)
+		fixed[12] = 0x11 // version 1, not supported
+		_, err := parseProxyV2(bufio.NewReader(bytes.NewReader(fixed)))
+		require.ErrorContains(t, err, "unsupported version")
+	})
+
+	t.Run("truncated_fixed_header", func(t *testing.T) {
+		_, err := parseProxyV2(bufio.NewReader(bytes.NewReader(make([]byte, 4))))
+		require.Error(t, err)
+	})
+
+	t.Run("truncated_address_body", func(t *testing.T) {
+		fixed := make([]byte, 16)
+		fixed[12] = 0x21
+		fixed[13] = 0x1 << 4
+		binary.BigEndian.PutUint16(fixed[14:16], 12) // declares 12 bytes, none follow
+		_, err := parseProxyV2(bufio.NewReader(bytes.NewReader(fixed)))
+		require.Error(t, err)
+	})
+
+	t.Run("short_ipv4_address_block", func(t *testing.T) {
+		raw := buildProxyV2(t, 0x1, make([]byte, 4))
+		_, err := parseProxyV2(bufio.NewReader(bytes.NewReader(raw)))
+		require.ErrorContains(t, err, "short IPv4 address block")
+	})
+}
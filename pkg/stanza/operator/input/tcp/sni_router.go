@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// sniRoute is a single resolved SNIRoute: a match predicate plus the
+// certificate to serve when it matches.
+type sniRoute struct {
+	match func(serverName string) bool
+	cert  *tls.Certificate
+}
+
+// sniRouter selects a TLS certificate based on the ClientHello's requested
+// server name, and remembers the negotiated name per-connection so it can be
+// attached to entries produced on that connection as net.tls.server_name.
+type sniRouter struct {
+	routes       []sniRoute
+	defaultCert  *tls.Certificate
+	requireMatch bool
+
+	mu    sync.Mutex
+	names map[net.Conn]string
+}
+
+// newSNIRouter builds a router from the configured routes. It returns a nil
+// router (and no error) when no routes are configured, so callers can treat
+// "no SNI routing" as a plain TLS listener.
+func newSNIRouter(routes []SNIRoute, fallback *tls.Config, requireMatch bool) (*sniRouter, error) {
+	if len(routes) == 0 {
+		return nil, nil
+	}
+
+	r := &sniRouter{
+		requireMatch: requireMatch,
+		names:        make(map[net.Conn]string),
+	}
+
+	for _, route := range routes {
+		if route.TLS == nil {
+			return nil, fmt.Errorf("sni_routes entry for %q is missing tls settings", route.ServerName)
+		}
+		cfg, err := route.TLS.LoadTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificate for sni_routes entry %q: %w", route.ServerName, err)
+		}
+		if len(cfg.Certificates) == 0 {
+			return nil, fmt.Errorf("no certificate configured for sni_routes entry %q", route.ServerName)
+		}
+		cert := cfg.Certificates[0]
+		r.routes = append(r.routes, sniRoute{
+			match: sniMatcher(route.ServerName),
+			cert:  &cert,
+		})
+	}
+
+	if fallback != nil && len(fallback.Certificates) > 0 {
+		cert := fallback.Certificates[0]
+		r.defaultCert = &cert
+	}
+
+	return r, nil
+}
+
+// getCertificate implements tls.Config.GetCertificate. It also records the
+// requested server name for the connection, keyed by the underlying raw
+// net.Conn, so readConnection can later tag emitted entries with it.
+func (r *sniRouter) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if hello.Conn != nil {
+		r.mu.Lock()
+		r.names[hello.Conn] = hello.ServerName
+		r.mu.Unlock()
+	}
+
+	for _, route := range r.routes {
+		if route.match(hello.ServerName) {
+			return route.cert, nil
+		}
+	}
+
+	if r.requireMatch {
+		return nil, fmt.Errorf("no certificate configured for SNI %q", hello.ServerName)
+	}
+
+	if r.defaultCert != nil {
+		return r.defaultCert, nil
+	}
+
+	return nil, fmt.Errorf("tcp_input: no certificate available and no default configured")
+}
+
+func (r *sniRouter) serverName(conn net.Conn) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name, ok := r.names[conn]
+	return name, ok
+}
+
+func (r *sniRouter) forget(conn net.Conn) {
+	r.mu.Lock()
+	delete(r.names, conn)
+	r.mu.Unlock()
+}
+
+// underlyingConn returns the raw net.Conn beneath a *tls.Conn, which is the
+// same value tls.ClientHelloInfo.Conn reports during the handshake.
+func underlyingConn(conn net.Conn) net.Conn {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		return tlsConn.NetConn()
+	}
+	return conn
+}
+
+// sniMatcher builds a match predicate for a single SNIRoute.ServerName
+// pattern. A leading "*." matches exactly one left-most DNS label.
+func sniMatcher(pattern string) func(string) bool {
+	if !strings.HasPrefix(pattern, "*.") {
+		return func(name string) bool { return name == pattern }
+	}
+
+	suffix := pattern[1:] // ".example.com"
+	return func(name string) bool {
+		if !strings.HasSuffix(name, suffix) {
+			return false
+		}
+		label := strings.TrimSuffix(name, suffix)
+		return label != "" && !strings.Contains(label, ".")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSNIMatcher(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "other.com", false},
+		{"*.example.com", "a.example.com", true},
+		{"*.example.com", "a.b.example.com", false},
+		{"*.example.com", "example.com", false},
+	}
+
+	for _, tc := range cases {
+		matcher := sniMatcher(tc.pattern)
+		require.Equal(t, tc.matches, matcher(tc.name), "pattern %q, name %q", tc.pattern, tc.name)
+	}
+}
+
+func newTestRouter(t *testing.T, requireMatch bool, withDefault bool) *sniRouter {
+	cert, err := tls.X509KeyPair([]byte(testTLSCertificate), []byte(testTLSPrivateKey))
+	require.NoError(t, err)
+
+	r := &sniRouter{
+		requireMatch: requireMatch,
+		names:        make(map[net.Conn]string),
+		routes: []sniRoute{
+			{match: sniMatcher("*.tenant.example.com"), cert: &cert},
+		},
+	}
+	if withDefault {
+		r.defaultCert = &cert
+	}
+	return r
+}
+
+func TestSNIRouterGetCertificate(t *testing.T) {
+	t.Run("matches a configured route", func(t *testing.T) {
+		r := newTestRouter(t, false, false)
+		cert, err := r.getCertificate(&tls.ClientHelloInfo{ServerName: "a.tenant.example.com"})
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+	})
+
+	t.Run("falls back to the default certificate", func(t *testing.T) {
+		r := newTestRouter(t, false, true)
+		cert, err := r.getCertificate(&tls.ClientHelloInfo{ServerName: "unrelated.example.org"})
+		require.NoError(t, err)
+		require.Same(t, r.defaultCert, cert)
+	})
+
+	t.Run("rejects an unmatched name when no default is set and matches are required", func(t *testing.T) {
+		r := newTestRouter(t, true, false)
+		_, err := r.getCertificate(&tls.ClientHelloInfo{ServerName: "unrelated.example.org"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,407 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcp // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/input/tcp"
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const (
+	defaultMaxLogSize = 1024 * 1024
+)
+
+func init() {
+	operator.Register("tcp_input", func() operator.Builder { return NewConfig("") })
+}
+
+// NewConfig creates a new TCP input config with default values
+func NewConfig(operatorID string) *Config {
+	return &Config{
+		InputConfig: helper.NewInputConfig(operatorID, "tcp_input"),
+	}
+}
+
+// Config is the configuration of a TCP input operator.
+type Config struct {
+	helper.InputConfig `yaml:",inline"`
+	BaseConfig         `yaml:",inline"`
+}
+
+// SNIRoute maps a TLS ClientHello server name to its own certificate, so a
+// single listener can terminate TLS for many logical tenants/products.
+type SNIRoute struct {
+	// ServerName matches a ClientHello SNI value. A leading "*." matches
+	// exactly one left-most label (e.g. "*.example.com" matches
+	// "a.example.com" but not "a.b.example.com" or "example.com").
+	ServerName string `json:"server_name,omitempty" yaml:"server_name,omitempty"`
+
+	// TLS is the certificate/key pair served to clients requesting ServerName.
+	TLS *helper.TLSServerConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// BaseConfig is the detailed configuration of a TCP input operator.
+type BaseConfig struct {
+	MaxLogSize    int                     `json:"max_log_size,omitempty" yaml:"max_log_size,omitempty"`
+	ListenAddress string                  `json:"listen_address,omitempty" yaml:"listen_address,omitempty"`
+	TLS           *helper.TLSServerConfig `json:"tls,omitempty" yaml:"tls,omitempty"`
+	AddAttributes bool                    `json:"add_attributes,omitempty" yaml:"add_attributes,omitempty"`
+
+	// SNIRoutes enables SNI-based multiplexing. When set, the certificate
+	// served for a given connection is chosen by matching the ClientHello's
+	// requested server name against each route, falling back to TLS (if
+	// set) as the default certificate.
+	SNIRoutes []SNIRoute `json:"sni_routes,omitempty" yaml:"sni_routes,omitempty"`
+
+	// SNIRequireMatch rejects the TLS handshake when no SNIRoute matches the
+	// requested server name, instead of falling back to the default TLS
+	// certificate.
+	SNIRequireMatch bool `json:"sni_require_match,omitempty" yaml:"sni_require_match,omitempty"`
+
+	// ProxyProtocol controls whether a PROXY protocol v1/v2 header is
+	// expected in front of each connection, ahead of TLS and log framing.
+	// One of "off" (default), "v1", "v2", "any", or "require". When a
+	// header is present, the attributes normally derived from the raw
+	// socket's remote address are instead derived from the header, and the
+	// raw L4 peer is additionally recorded under net.peer.proxy.*.
+	ProxyProtocol ProxyProtocolMode `json:"proxy_protocol,omitempty" yaml:"proxy_protocol,omitempty"`
+
+	// Framing selects how the byte stream is split into entries: "newline"
+	// (default), "null", "octet-counted" (RFC 6587), or "custom".
+	Framing FramingMode `json:"framing,omitempty" yaml:"framing,omitempty"`
+
+	// FramingRegex is the delimiter pattern used when Framing is "custom".
+	FramingRegex string `json:"framing_regex,omitempty" yaml:"framing_regex,omitempty"`
+
+	// CRLFiles lists CRL files consulted on every handshake to reject
+	// connections presenting a revoked client certificate. Requires TLS's
+	// ClientCAFile to be set, since client certificates must be requested
+	// and verified before a CRL check is meaningful.
+	CRLFiles []string `json:"crl_files,omitempty" yaml:"crl_files,omitempty"`
+
+	// OCSPStapleVerify additionally checks each client certificate's chain
+	// against its issuer's OCSP responder.
+	OCSPStapleVerify bool `json:"ocsp_staple_verify,omitempty" yaml:"ocsp_staple_verify,omitempty"`
+
+	// MaxConnections caps the number of simultaneously open connections
+	// across all remote IPs. Zero means unlimited.
+	MaxConnections int `json:"max_connections,omitempty" yaml:"max_connections,omitempty"`
+
+	// MaxConnectionsPerIP caps the number of simultaneously open
+	// connections from a single remote IP. Zero means unlimited.
+	MaxConnectionsPerIP int `json:"max_connections_per_ip,omitempty" yaml:"max_connections_per_ip,omitempty"`
+
+	// BytesPerSecondPerConn throttles each connection's read rate to this
+	// many bytes per second. Zero means unlimited.
+	BytesPerSecondPerConn int `json:"bytes_per_second_per_conn,omitempty" yaml:"bytes_per_second_per_conn,omitempty"`
+}
+
+// Build will build a TCP input operator.
+func (c *Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	inputOperator, err := c.InputConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ListenAddress == "" {
+		return nil, fmt.Errorf("missing required parameter 'listen_address'")
+	}
+
+	if c.MaxLogSize < 0 {
+		return nil, fmt.Errorf("invalid value for parameter 'max_log_size', must be greater than zero")
+	}
+
+	switch c.ProxyProtocol {
+	case "", ProxyProtocolOff, ProxyProtocolV1, ProxyProtocolV2, ProxyProtocolAny, ProxyProtocolRequire:
+	default:
+		return nil, fmt.Errorf("invalid value for parameter 'proxy_protocol': %q", c.ProxyProtocol)
+	}
+
+	maxLogSize := c.MaxLogSize
+	if maxLogSize == 0 {
+		maxLogSize = defaultMaxLogSize
+	}
+
+	splitFunc, err := newSplitFunc(c.Framing, c.FramingRegex, maxLogSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure framing: %w", err)
+	}
+
+	var tlsConfig *tls.Config
+	if c.TLS != nil {
+		tlsConfig, err = c.TLS.LoadTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+	}
+
+	router, err := newSNIRouter(c.SNIRoutes, tlsConfig, c.SNIRequireMatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure sni_routes: %w", err)
+	}
+	if router != nil {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		tlsConfig.GetCertificate = router.getCertificate
+	}
+
+	if tlsConfig != nil && tlsConfig.ClientCAs != nil {
+		// A ClientCAFile was configured: require and verify the client
+		// certificate rather than merely accepting one if offered.
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	if len(c.CRLFiles) > 0 {
+		if tlsConfig == nil || tlsConfig.ClientCAs == nil {
+			return nil, fmt.Errorf("'crl_files' requires 'tls.client_ca_file' to be set")
+		}
+		crl, err := newCRLVerifier(c.CRLFiles)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure crl_files: %w", err)
+		}
+		tlsConfig.VerifyPeerCertificate = chainVerifiers(tlsConfig.VerifyPeerCertificate, crl.verifyPeerCertificate)
+	}
+
+	if c.OCSPStapleVerify {
+		if tlsConfig == nil || tlsConfig.ClientCAs == nil {
+			return nil, fmt.Errorf("'ocsp_staple_verify' requires 'tls.client_ca_file' to be set")
+		}
+		tlsConfig.VerifyPeerCertificate = chainVerifiers(tlsConfig.VerifyPeerCertificate, verifyOCSPStaple)
+	}
+
+	metrics, err := newConnectionMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure connection metrics: %w", err)
+	}
+
+	return &Input{
+		InputOperator:         inputOperator,
+		address:               c.ListenAddress,
+		maxLogSize:            maxLogSize,
+		addAttributes:         c.AddAttributes,
+		tlsConfig:             tlsConfig,
+		sniRouter:             router,
+		proxyProtocol:         c.ProxyProtocol,
+		splitFunc:             splitFunc,
+		bytesPerSecondPerConn: c.BytesPerSecondPerConn,
+		connLimiter:           newConnLimiter(c.MaxConnections, c.MaxConnectionsPerIP),
+		metrics:               metrics,
+		resolver:              helper.NewIPResolver(),
+	}, nil
+}
+
+// Input is an operator that listens for log entries over TCP.
+type Input struct {
+	helper.InputOperator
+
+	address               string
+	maxLogSize            int
+	addAttributes         bool
+	tlsConfig             *tls.Config
+	sniRouter             *sniRouter
+	proxyProtocol         ProxyProtocolMode
+	splitFunc             bufio.SplitFunc
+	bytesPerSecondPerConn int
+	connLimiter           *connLimiter
+	metrics               *connectionMetrics
+	resolver              *helper.IPResolver
+
+	listener net.Listener
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// Start will start listening for log entries over TCP.
+func (t *Input) Start(_ operator.Persister) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+
+	listener, err := net.Listen("tcp", t.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", t.address, err)
+	}
+	t.listener = listener
+
+	t.goAcceptLoop(ctx)
+
+	return nil
+}
+
+func (t *Input) goAcceptLoop(ctx context.Context) {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		for {
+			conn, err := t.listener.Accept()
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				t.Errorf("Failed to accept connection", zap.Error(err))
+				return
+			}
+
+			t.wg.Add(1)
+			go func() {
+				defer t.wg.Done()
+				t.handleConnection(ctx, conn)
+			}()
+		}
+	}()
+}
+
+// handleConnection peels off an optional PROXY protocol header, performs the
+// TLS handshake (if configured), and then reads log entries from the
+// resulting connection. TLS is wrapped per-connection, rather than at the
+// listener level, so that any PROXY protocol header (which arrives in the
+// clear ahead of the TLS ClientHello) can be consumed first.
+func (t *Input) handleConnection(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	ip := remoteIP(conn)
+	if !t.connLimiter.acquire(ip) {
+		t.metrics.addRejected(ctx)
+		return
+	}
+	defer t.connLimiter.release(ip)
+	t.metrics.addAccepted(ctx)
+	t.metrics.addActive(ctx, 1)
+	defer t.metrics.addActive(ctx, -1)
+
+	conn = newRateLimitedConn(ctx, conn, t.bytesPerSecondPerConn, t.metrics)
+
+	conn, proxyHdr, err := t.readProxyHeader(conn)
+	if err != nil {
+		t.metrics.addParseError(ctx)
+		t.Errorf("Rejecting connection", zap.Error(err))
+		return
+	}
+
+	var peerTLS map[string]interface{}
+	if t.tlsConfig != nil {
+		tlsConn := tls.Server(conn, t.tlsConfig)
+		if t.sniRouter != nil {
+			// getCertificate records the ClientHello's server name as soon as
+			// it's seen, before the handshake completes, so forget must be
+			// registered here rather than after a successful handshake - a
+			// client that presents an unacceptable SNI name and aborts the
+			// handshake would otherwise leak its entry in sniRouter.names
+			// forever.
+			defer t.sniRouter.forget(underlyingConn(tlsConn))
+		}
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			t.metrics.addParseError(ctx)
+			t.Errorf("Failed to handshake with TLS connection", zap.Error(err))
+			return
+		}
+		conn = tlsConn
+		peerTLS = peerTLSAttributes(tlsConn.ConnectionState())
+	}
+
+	t.readConnection(ctx, conn, proxyHdr, peerTLS)
+}
+
+func (t *Input) readConnection(ctx context.Context, conn net.Conn, proxyHdr *proxyHeader, peerTLS map[string]interface{}) {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, t.maxLogSize), t.maxLogSize)
+	scanner.Split(t.splitFunc)
+
+	for scanner.Scan() {
+		t.emit(ctx, conn, proxyHdr, peerTLS, scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil {
+		t.metrics.addParseError(ctx)
+		t.Errorf("Error reading connection", zap.Error(err))
+	}
+}
+
+func (t *Input) emit(ctx context.Context, conn net.Conn, proxyHdr *proxyHeader, peerTLS map[string]interface{}, token []byte) {
+	e := entry.New()
+	e.Body = string(token)
+
+	if t.addAttributes {
+		e.Attributes = map[string]interface{}{
+			"net.transport": "IP.TCP",
+		}
+		if addr, ok := conn.LocalAddr().(*net.TCPAddr); ok {
+			ip := addr.IP.String()
+			e.Attributes["net.host.ip"] = addr.IP.String()
+			e.Attributes["net.host.port"] = strconv.FormatInt(int64(addr.Port), 10)
+			e.Attributes["net.host.name"] = t.resolver.GetHostFromIP(ip)
+		}
+		if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+			ip := addr.IP.String()
+			e.Attributes["net.peer.ip"] = ip
+			e.Attributes["net.peer.port"] = strconv.FormatInt(int64(addr.Port), 10)
+			e.Attributes["net.peer.name"] = t.resolver.GetHostFromIP(ip)
+
+			if proxyHdr != nil && proxyHdr.SourceIP != "" {
+				// The PROXY header reports the real client; the raw L4 peer
+				// (typically the load balancer) is kept under net.peer.proxy.*
+				// rather than discarded.
+				e.Attributes["net.peer.proxy.ip"] = ip
+				e.Attributes["net.peer.proxy.port"] = strconv.FormatInt(int64(addr.Port), 10)
+				e.Attributes["net.peer.ip"] = proxyHdr.SourceIP
+				e.Attributes["net.peer.port"] = strconv.FormatInt(int64(proxyHdr.SourcePort), 10)
+				e.Attributes["net.peer.name"] = t.resolver.GetHostFromIP(proxyHdr.SourceIP)
+			}
+		}
+	}
+
+	if t.sniRouter != nil {
+		if name, ok := t.sniRouter.serverName(underlyingConn(conn)); ok && name != "" {
+			if e.Attributes == nil {
+				e.Attributes = map[string]interface{}{}
+			}
+			e.Attributes["net.tls.server_name"] = name
+		}
+	}
+
+	for k, v := range peerTLS {
+		if e.Attributes == nil {
+			e.Attributes = map[string]interface{}{}
+		}
+		e.Attributes[k] = v
+	}
+
+	t.Write(ctx, e)
+}
+
+// Stop will stop listening for log entries over TCP.
+func (t *Input) Stop() error {
+	if t.cancel == nil {
+		return nil
+	}
+	t.cancel()
+	if t.listener != nil {
+		if err := t.listener.Close(); err != nil {
+			t.Errorf("Failed to close listener", zap.Error(err))
+		}
+	}
+	t.wg.Wait()
+	return nil
+}
@@ -466,6 +466,56 @@ func BenchmarkTCPInput(b *testing.B) {
 	defer close(done)
 }
 
+func TestSNIRouterForgetsEntryOnFailedHandshake(t *testing.T) {
+	f, err := os.Create("sni_test.crt")
+	require.NoError(t, err)
+	defer os.Remove("sni_test.crt")
+	_, err = f.WriteString(testTLSCertificate + "\n")
+	require.NoError(t, err)
+	f.Close()
+
+	f, err = os.Create("sni_test.key")
+	require.NoError(t, err)
+	defer os.Remove("sni_test.key")
+	_, err = f.WriteString(testTLSPrivateKey + "\n")
+	require.NoError(t, err)
+	f.Close()
+
+	cfg := NewConfig("test_id")
+	cfg.ListenAddress = ":0"
+	cfg.SNIRequireMatch = true
+	cfg.SNIRoutes = []SNIRoute{
+		{ServerName: "tenant.example.com", TLS: createTLSConfig("sni_test.crt", "sni_test.key")},
+	}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	tcpInput := op.(*Input)
+	require.NoError(t, tcpInput.Start(testutil.NewMockPersister("test")))
+	defer func() {
+		require.NoError(t, tcpInput.Stop())
+	}()
+
+	// unrelated.example.org matches no configured route, and SNIRequireMatch
+	// rejects it instead of falling back to a default cert, so the handshake
+	// fails after getCertificate has already recorded the connection.
+	conn, dialErr := tls.Dial("tcp", tcpInput.listener.Addr().String(), &tls.Config{
+		ServerName:         "unrelated.example.org",
+		InsecureSkipVerify: true,
+	})
+	require.Error(t, dialErr)
+	if conn != nil {
+		conn.Close()
+	}
+
+	require.Eventually(t, func() bool {
+		tcpInput.sniRouter.mu.Lock()
+		defer tcpInput.sniRouter.mu.Unlock()
+		return len(tcpInput.sniRouter.names) == 0
+	}, time.Second, 10*time.Millisecond, "sniRouter leaked an entry for a connection whose handshake failed")
+}
+
 func createTLSConfig(cert string, key string) *helper.TLSServerConfig {
 	return helper.NewTLSServerConfig(&configtls.TLSServerSetting{
 		TLSSetting: configtls.TLSSetting{
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestBuildAndProcessArrayPatterns(t *testing.T) {
+	now := time.Now()
+	newTestEntry := func() *entry.Entry {
+		e := entry.New()
+		e.ObservedTimestamp = now
+		e.Timestamp = time.Unix(1586632809, 0)
+		e.Body = map[string]interface{}{
+			"events": []interface{}{
+				map[string]interface{}{"id": "1", "verb": "get"},
+				map[string]interface{}{"id": "2", "verb": "list"},
+				map[string]interface{}{"id": "3", "verb": "delete"},
+			},
+			"other": "value",
+		}
+		return e
+	}
+
+	cases := []testCase{
+		{
+			"single_index_keeps_only_that_element",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{"body.events[0]"}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"events": []interface{}{
+						map[string]interface{}{"id": "1", "verb": "get"},
+					},
+				}
+				return e
+			},
+		},
+		{
+			"wildcard_index_projects_subfield_across_elements",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{"body.events[*].id"}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"events": []interface{}{
+						map[string]interface{}{"id": "1"},
+						map[string]interface{}{"id": "2"},
+						map[string]interface{}{"id": "3"},
+					},
+				}
+				return e
+			},
+		},
+		{
+			"non_contiguous_indices_are_compacted_preserving_order",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{"body.events[0].id", "body.events[2].id"}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"events": []interface{}{
+						map[string]interface{}{"id": "1"},
+						map[string]interface{}{"id": "3"},
+					},
+				}
+				return e
+			},
+		},
+		{
+			"out_of_range_index_is_a_no_op",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{"body.events[99].id", "body.other"}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"other": "value",
+				}
+				return e
+			},
+		},
+		{
+			"remove_mode_drops_only_the_indexed_element",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Mode = ModeRemove
+				cfg.Patterns = []string{"body.events[1]"}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"events": []interface{}{
+						map[string]interface{}{"id": "1", "verb": "get"},
+						map[string]interface{}{"id": "3", "verb": "delete"},
+					},
+					"other": "value",
+				}
+				return e
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("BuildAndProcessArrayPatterns/"+tc.name, func(t *testing.T) {
+			cfg := tc.op
+			cfg.OutputIDs = []string{"fake"}
+			cfg.OnError = "drop"
+			op, err := cfg.Build(testutil.Logger(t))
+			require.NoError(t, err)
+
+			project := op.(*ProjectOperator)
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, project.SetOutputs([]operator.Operator{fake}))
+			val := tc.input()
+			err = project.Process(context.Background(), val)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				fake.ExpectEntry(t, tc.output())
+			}
+		})
+	}
+}
+
+func TestParseSegmentInvalidIndex(t *testing.T) {
+	_, err := parseFieldPattern("body.events[abc]")
+	require.Error(t, err)
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+
+import (
+	"fmt"
+
+	"github.com/antonmedv/expr/vm"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// FieldCondition matches Field only for entries satisfying the When
+// expression, letting a single project operator treat a field differently
+// across entries (e.g. retaining full bodies on error logs but not info
+// logs) in one pipeline stage.
+type FieldCondition struct {
+	Field entry.Field `mapstructure:"field"`
+	When  string      `mapstructure:"when"`
+}
+
+// compiledFieldCondition is a FieldCondition with its When expression
+// compiled once at Build time, rather than on every entry.
+type compiledFieldCondition struct {
+	field   entry.Field
+	program *vm.Program
+}
+
+func (c FieldCondition) compile() (compiledFieldCondition, error) {
+	if c.When == "" {
+		return compiledFieldCondition{}, fmt.Errorf("project: conditional_fields entry for %s is missing 'when'", c.Field)
+	}
+	program, err := helper.ExprCompileBool(c.When)
+	if err != nil {
+		return compiledFieldCondition{}, fmt.Errorf("project: failed to compile 'when' for %s: %w", c.Field, err)
+	}
+	return compiledFieldCondition{field: c.Field, program: program}, nil
+}
+
+// matches evaluates the condition's When expression against ent, the same
+// way the stanza expression engine evaluates every other operator's 'if'.
+func (c compiledFieldCondition) matches(ent *entry.Entry) (bool, error) {
+	env := helper.GetExprEnv(ent)
+	defer helper.PutExprEnv(env)
+
+	result, err := vm.Run(c.program, env)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("'when' expression for %s did not evaluate to a boolean", c.field)
+	}
+	return matched, nil
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+
+// projectMetrics exposes a project operator's field-selection behavior as
+// OpenTelemetry instruments. Ideally this would be instrumented through the
+// collector's component.TelemetrySettings like an exporter or receiver is,
+// but operator.Builder.Build only ever receives a *zap.SugaredLogger, and
+// that interface lives outside this package, so there is no way to thread a
+// MeterProvider in from here. This follows the same global-meter pattern as
+// the TCP input's connection metrics for the same reason.
+type projectMetrics struct {
+	mode             Mode
+	entriesProcessed metric.Int64Counter
+	fieldsMatched    metric.Int64Counter
+	fieldsMissing    metric.Int64Counter
+	entriesEmptied   metric.Int64Counter
+}
+
+func newProjectMetrics(mode Mode) (*projectMetrics, error) {
+	meter := otel.Meter(meterName)
+
+	entriesProcessed, err := meter.Int64Counter(
+		"otelcol_stanza_project_entries_processed",
+		metric.WithDescription("Number of entries processed by a project (retain/remove) operator"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entries processed counter: %w", err)
+	}
+
+	fieldsMatched, err := meter.Int64Counter(
+		"otelcol_stanza_project_fields_matched",
+		metric.WithDescription("Number of fields successfully retained or removed by a project operator"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fields matched counter: %w", err)
+	}
+
+	fieldsMissing, err := meter.Int64Counter(
+		"otelcol_stanza_project_fields_missing",
+		metric.WithDescription("Number of configured fields not present on the entry they were matched against"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fields missing counter: %w", err)
+	}
+
+	entriesEmptied, err := meter.Int64Counter(
+		"otelcol_stanza_project_entries_emptied",
+		metric.WithDescription("Number of entries where retain mode left a targeted body/attributes/resource nil"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create entries emptied counter: %w", err)
+	}
+
+	return &projectMetrics{
+		mode:             mode,
+		entriesProcessed: entriesProcessed,
+		fieldsMatched:    fieldsMatched,
+		fieldsMissing:    fieldsMissing,
+		entriesEmptied:   entriesEmptied,
+	}, nil
+}
+
+func (m *projectMetrics) addEntryProcessed(ctx context.Context) {
+	m.entriesProcessed.Add(ctx, 1, metric.WithAttributes(attribute.String("mode", string(m.mode))))
+}
+
+// addFieldMatched records a match against field, which must be a value whose
+// cardinality is bounded by the operator's configuration (a literal Fields
+// entry's dotted path, or a Patterns entry exactly as configured) rather
+// than a concrete resolved path — the latter can be unbounded when a "[*]"
+// pattern matches into a large or unbounded array.
+func (m *projectMetrics) addFieldMatched(ctx context.Context, field string) {
+	m.fieldsMatched.Add(ctx, 1, metric.WithAttributes(attribute.String("field", field)))
+}
+
+// addFieldMissing records a miss against field; see addFieldMatched for the
+// cardinality constraint on field.
+func (m *projectMetrics) addFieldMissing(ctx context.Context, field string) {
+	m.fieldsMissing.Add(ctx, 1, metric.WithAttributes(attribute.String("field", field)))
+}
+
+func (m *projectMetrics) addEntryEmptied(ctx context.Context) {
+	m.entriesEmptied.Add(ctx, 1, metric.WithAttributes(attribute.String("mode", string(m.mode))))
+}
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stepKind distinguishes the two kinds of pathStep.
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+)
+
+// pathStep is one elementary step of a patternMatch's resolved path: either
+// a map-key descend or an array-index descend. A Patterns segment like
+// "events[0]" produces two steps, {stepKey, "events"} then {stepIndex, 0}.
+type pathStep struct {
+	Kind  stepKind
+	Key   string
+	Index int
+}
+
+// pathString renders section and path as a concrete dotted path, e.g.
+// "body.events[0].id" (wildcards appear as the index or key they matched) —
+// used for debug logs.
+func pathString(section section, path []pathStep) string {
+	var b strings.Builder
+	b.WriteString(string(section))
+	for _, step := range path {
+		switch step.Kind {
+		case stepKey:
+			b.WriteByte('.')
+			b.WriteString(step.Key)
+		case stepIndex:
+			b.WriteByte('[')
+			b.WriteString(strconv.Itoa(step.Index))
+			b.WriteByte(']')
+		}
+	}
+	return b.String()
+}
+
+// getAtPath reads the value at path within root, a section root
+// (map[string]interface{}, []interface{}, or nil).
+func getAtPath(root interface{}, path []pathStep) (interface{}, bool) {
+	cur := root
+	for _, step := range path {
+		switch step.Kind {
+		case stepKey:
+			m, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			cur, ok = m[step.Key]
+			if !ok {
+				return nil, false
+			}
+		case stepIndex:
+			s, ok := cur.([]interface{})
+			if !ok || step.Index < 0 || step.Index >= len(s) {
+				return nil, false
+			}
+			cur = s[step.Index]
+		}
+	}
+	return cur, true
+}
+
+// deleteAtPath removes path from root in place where possible, returning the
+// updated root and whether anything was actually removed. Deleting an array
+// element splices it out, shifting later elements down by one.
+func deleteAtPath(root interface{}, path []pathStep) (interface{}, bool) {
+	if len(path) == 0 {
+		return root, false
+	}
+	step, rest := path[0], path[1:]
+
+	switch step.Kind {
+	case stepKey:
+		m, ok := root.(map[string]interface{})
+		if !ok {
+			return root, false
+		}
+		child, ok := m[step.Key]
+		if !ok {
+			return root, false
+		}
+		if len(rest) == 0 {
+			delete(m, step.Key)
+			return m, true
+		}
+		newChild, deleted := deleteAtPath(child, rest)
+		if deleted {
+			m[step.Key] = newChild
+		}
+		return m, deleted
+	case stepIndex:
+		s, ok := root.([]interface{})
+		if !ok || step.Index < 0 || step.Index >= len(s) {
+			return root, false
+		}
+		if len(rest) == 0 {
+			s = append(s[:step.Index], s[step.Index+1:]...)
+			return s, true
+		}
+		newChild, deleted := deleteAtPath(s[step.Index], rest)
+		if deleted {
+			s[step.Index] = newChild
+		}
+		return s, deleted
+	}
+	return root, false
+}
+
+// treeNode accumulates pattern matches into a sparse tree before being
+// finalized into a real map[string]interface{}/[]interface{} value. Array
+// children are keyed by their ORIGINAL index rather than written directly
+// into a real slice, so a final compaction pass can drop every position
+// that was never matched while keeping the matched elements in their
+// original relative order: retaining events[0] and events[2] out of a
+// five-element array yields a two-element array, not a five-element array
+// with three holes.
+type treeNode struct {
+	mapKids map[string]*treeNode
+	arrKids map[int]*treeNode
+	leaf    interface{}
+	hasLeaf bool
+}
+
+func (n *treeNode) descend(step pathStep) *treeNode {
+	switch step.Kind {
+	case stepKey:
+		if n.mapKids == nil {
+			n.mapKids = map[string]*treeNode{}
+		}
+		child, ok := n.mapKids[step.Key]
+		if !ok {
+			child = &treeNode{}
+			n.mapKids[step.Key] = child
+		}
+		return child
+	default:
+		if n.arrKids == nil {
+			n.arrKids = map[int]*treeNode{}
+		}
+		child, ok := n.arrKids[step.Index]
+		if !ok {
+			child = &treeNode{}
+			n.arrKids[step.Index] = child
+		}
+		return child
+	}
+}
+
+// set records val at path, creating intermediate nodes as needed.
+func (n *treeNode) set(path []pathStep, val interface{}) {
+	cur := n
+	for _, step := range path {
+		cur = cur.descend(step)
+	}
+	cur.leaf = val
+	cur.hasLeaf = true
+}
+
+// finalize converts the sparse tree into a real value: a compacted
+// []interface{} if it has array children, a map[string]interface{} if it
+// has map children, or the recorded leaf otherwise.
+func (n *treeNode) finalize() interface{} {
+	switch {
+	case len(n.arrKids) > 0:
+		indices := make([]int, 0, len(n.arrKids))
+		for idx := range n.arrKids {
+			indices = append(indices, idx)
+		}
+		sort.Ints(indices)
+		arr := make([]interface{}, len(indices))
+		for i, idx := range indices {
+			arr[i] = n.arrKids[idx].finalize()
+		}
+		return arr
+	case len(n.mapKids) > 0:
+		m := make(map[string]interface{}, len(n.mapKids))
+		for k, child := range n.mapKids {
+			m[k] = child.finalize()
+		}
+		return m
+	default:
+		return n.leaf
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+)
+
+// section identifies which part of an entry a field or pattern targets.
+type section string
+
+const (
+	sectionBody       section = "body"
+	sectionAttributes section = "attributes"
+	sectionResource   section = "resource"
+)
+
+// fieldSection reports which section a literal entry.Field targets, based on
+// the prefix of its dotted string representation (e.g. "body.foo",
+// "attributes.foo", "resource.foo").
+func fieldSection(f entry.Field) section {
+	s := f.String()
+	switch {
+	case strings.HasPrefix(s, string(sectionBody)):
+		return sectionBody
+	case strings.HasPrefix(s, string(sectionAttributes)):
+		return sectionAttributes
+	case strings.HasPrefix(s, string(sectionResource)):
+		return sectionResource
+	default:
+		return ""
+	}
+}
+
+// segmentSpec is a single, dot-separated piece of a Patterns entry, e.g.
+// "events[0]", "events[*]", "*", or "**". A segment may carry a map-key
+// component (concrete, "*", or absent), an array-index component (concrete,
+// "*", or absent), or both, e.g. "events[0]" descends into key "events" and
+// then into index 0 of the array found there.
+type segmentSpec struct {
+	globStar bool // "**": stop here, keep the remaining subtree as-is
+
+	hasKey      bool
+	key         string
+	keyWildcard bool // "*" as the key component
+
+	hasIndex      bool
+	index         int
+	indexWildcard bool // "*" as the index component
+}
+
+var segmentPattern = regexp.MustCompile(`^([^\[\]]*)(\[(\*|\d+)\])?$`)
+
+// parseSegment parses one dot-separated piece of a Patterns entry.
+func parseSegment(raw string) (segmentSpec, error) {
+	if raw == "**" {
+		return segmentSpec{globStar: true}, nil
+	}
+
+	m := segmentPattern.FindStringSubmatch(raw)
+	if m == nil {
+		return segmentSpec{}, fmt.Errorf("invalid path segment %q", raw)
+	}
+
+	var spec segmentSpec
+	if keyPart := m[1]; keyPart != "" {
+		spec.hasKey = true
+		if keyPart == "*" {
+			spec.keyWildcard = true
+		} else {
+			spec.key = keyPart
+		}
+	}
+	if m[2] != "" {
+		spec.hasIndex = true
+		if m[3] == "*" {
+			spec.indexWildcard = true
+		} else {
+			idx, err := strconv.Atoi(m[3])
+			if err != nil {
+				return segmentSpec{}, fmt.Errorf("invalid index in path segment %q: %w", raw, err)
+			}
+			spec.index = idx
+		}
+	}
+	if !spec.hasKey && !spec.hasIndex {
+		return segmentSpec{}, fmt.Errorf("empty path segment")
+	}
+	return spec, nil
+}
+
+// fieldPattern is a parsed Patterns entry: either a sequence of segments
+// (Segments set, possibly indexing into arrays) or a regular expression
+// (Regex set), scoped to a single Section. Raw is the pattern exactly as
+// configured, e.g. "body.events[*].id"; it is bounded by the number of
+// configured Patterns entries, unlike the concrete paths it expands to, and
+// is what metrics attribute matches to (see patternMatch.Pattern).
+type fieldPattern struct {
+	Raw      string
+	Section  section
+	Segments []segmentSpec
+	Regex    *regexp.Regexp
+}
+
+// parseFieldPattern parses a single Patterns string, "<section>.<rest>".
+func parseFieldPattern(raw string) (fieldPattern, error) {
+	parts := strings.SplitN(raw, ".", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return fieldPattern{}, fmt.Errorf("project: invalid pattern %q: expected '<body|attributes|resource>.<pattern>'", raw)
+	}
+
+	sec := section(parts[0])
+	switch sec {
+	case sectionBody, sectionAttributes, sectionResource:
+	default:
+		return fieldPattern{}, fmt.Errorf("project: invalid pattern %q: unknown section %q", raw, parts[0])
+	}
+
+	rest := parts[1]
+	if regexSrc, ok := strings.CutPrefix(rest, "regex:"); ok {
+		re, err := regexp.Compile(regexSrc)
+		if err != nil {
+			return fieldPattern{}, fmt.Errorf("project: invalid pattern %q: %w", raw, err)
+		}
+		return fieldPattern{Raw: raw, Section: sec, Regex: re}, nil
+	}
+
+	rawSegments := strings.Split(rest, ".")
+	segments := make([]segmentSpec, 0, len(rawSegments))
+	for _, rawSeg := range rawSegments {
+		seg, err := parseSegment(rawSeg)
+		if err != nil {
+			return fieldPattern{}, fmt.Errorf("project: invalid pattern %q: %w", raw, err)
+		}
+		segments = append(segments, seg)
+	}
+	return fieldPattern{Raw: raw, Section: sec, Segments: segments}, nil
+}
+
+// patternMatch is one concrete match produced by expanding a fieldPattern
+// against an entry: a section plus the exact path (map keys and array
+// indices) of the matched value within that section. Pattern carries the
+// fieldPattern.Raw it was expanded from, for attributing metrics without
+// leaking the concrete, potentially unbounded-cardinality path (e.g. a
+// "[*]" match against a large array) into a label value.
+type patternMatch struct {
+	Section section
+	Path    []pathStep
+	Pattern string
+}
+
+// expand resolves p against ent's matching section, returning one
+// patternMatch per matched leaf (or, for a trailing "**" segment or array
+// index, every matched subtree root or array element).
+func (p fieldPattern) expand(ent *entry.Entry) []patternMatch {
+	var root interface{}
+	switch p.Section {
+	case sectionBody:
+		root = ent.Body
+	case sectionAttributes:
+		root = ent.Attributes
+	case sectionResource:
+		root = ent.Resource
+	}
+
+	var paths [][]pathStep
+	if p.Regex != nil {
+		for _, keys := range matchRegexPaths(root, nil, p.Regex) {
+			steps := make([]pathStep, len(keys))
+			for i, k := range keys {
+				steps[i] = pathStep{Kind: stepKey, Key: k}
+			}
+			paths = append(paths, steps)
+		}
+	} else {
+		paths = matchGlobPaths(root, p.Segments)
+	}
+
+	matches := make([]patternMatch, 0, len(paths))
+	for _, path := range paths {
+		matches = append(matches, patternMatch{Section: p.Section, Path: path, Pattern: p.Raw})
+	}
+	return matches
+}
+
+// matchGlobPaths returns the concrete path (map keys and array indices) of
+// every value under node that matches segments. "*" matches any single map
+// key, "[*]" matches any single array index, and a trailing "**" matches
+// the node reached so far in its entirety (its whole subtree is kept as-is).
+func matchGlobPaths(node interface{}, segments []segmentSpec) [][]pathStep {
+	if len(segments) == 0 {
+		return [][]pathStep{{}}
+	}
+	seg := segments[0]
+	if seg.globStar {
+		return [][]pathStep{{}}
+	}
+	rest := segments[1:]
+
+	type resolved struct {
+		steps []pathStep
+		value interface{}
+	}
+
+	var afterKey []resolved
+	if seg.hasKey {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if seg.keyWildcard {
+			for k, v := range m {
+				afterKey = append(afterKey, resolved{steps: []pathStep{{Kind: stepKey, Key: k}}, value: v})
+			}
+		} else if v, ok := m[seg.key]; ok {
+			afterKey = append(afterKey, resolved{steps: []pathStep{{Kind: stepKey, Key: seg.key}}, value: v})
+		}
+	} else {
+		afterKey = append(afterKey, resolved{value: node})
+	}
+
+	var afterIndex []resolved
+	if !seg.hasIndex {
+		afterIndex = afterKey
+	} else {
+		for _, r := range afterKey {
+			arr, ok := r.value.([]interface{})
+			if !ok {
+				continue
+			}
+			if seg.indexWildcard {
+				for idx := range arr {
+					steps := append(append([]pathStep{}, r.steps...), pathStep{Kind: stepIndex, Index: idx})
+					afterIndex = append(afterIndex, resolved{steps: steps, value: arr[idx]})
+				}
+			} else if seg.index >= 0 && seg.index < len(arr) {
+				steps := append(append([]pathStep{}, r.steps...), pathStep{Kind: stepIndex, Index: seg.index})
+				afterIndex = append(afterIndex, resolved{steps: steps, value: arr[seg.index]})
+			}
+		}
+	}
+
+	var matches [][]pathStep
+	for _, r := range afterIndex {
+		for _, sub := range matchGlobPaths(r.value, rest) {
+			matches = append(matches, append(append([]pathStep{}, r.steps...), sub...))
+		}
+	}
+	return matches
+}
+
+// matchRegexPaths walks node, matching each map node's dotted path (relative
+// to the section root) against re. A matching node, leaf or not, is recorded
+// and not descended into further; a non-matching map node is recursed into
+// to look for deeper matches. Regex patterns only descend into maps; use a
+// segment pattern (e.g. "events[*].id") to match into arrays.
+func matchRegexPaths(node interface{}, prefix []string, re *regexp.Regexp) [][]string {
+	if len(prefix) > 0 && re.MatchString(strings.Join(prefix, ".")) {
+		return [][]string{append([]string{}, prefix...)}
+	}
+
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var matches [][]string
+	for k, v := range m {
+		next := append(append([]string{}, prefix...), k)
+		matches = append(matches, matchRegexPaths(v, next, re)...)
+	}
+	return matches
+}
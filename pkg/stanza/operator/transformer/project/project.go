@@ -0,0 +1,356 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package project provides the shared field-selection engine behind the
+// retain and remove operators: both configure a set of fields (literal,
+// pattern matched, or conditionally matched) and differ only in whether
+// those fields are the ones to keep (retain) or the ones to drop (remove).
+package project // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+// missingFieldLogSampleRate logs roughly 1 in every N missing-field events
+// when DebugMissingFields is enabled, so a badly misconfigured pipeline
+// doesn't flood logs under load.
+const missingFieldLogSampleRate = 100
+
+const operatorType = "project"
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewProjectOperatorConfig("") })
+}
+
+// Mode selects whether a ProjectOperator's fields are the ones to keep or
+// the ones to drop.
+type Mode string
+
+const (
+	// ModeRetain keeps only the configured fields, rebuilding any section
+	// (body/attributes/resource) that a literal field targets or a pattern
+	// matches into. A pattern that matches nothing leaves its section as is.
+	ModeRetain Mode = "retain"
+	// ModeRemove drops only the configured fields, leaving everything else
+	// untouched.
+	ModeRemove Mode = "remove"
+)
+
+// NewProjectOperatorConfig creates a new project operator config with default values.
+func NewProjectOperatorConfig(operatorID string) *ProjectOperatorConfig {
+	return &ProjectOperatorConfig{
+		TransformerConfig: helper.NewTransformerConfig(operatorID, operatorType),
+		Mode:              ModeRetain,
+	}
+}
+
+func defaultCfg() *ProjectOperatorConfig {
+	return NewProjectOperatorConfig("")
+}
+
+// ProjectOperatorConfig is the configuration of a project operator.
+type ProjectOperatorConfig struct {
+	helper.TransformerConfig `mapstructure:",squash"`
+
+	// Mode is either "retain" (keep only the matched fields) or "remove"
+	// (drop only the matched fields). Defaults to "retain".
+	Mode Mode `mapstructure:"mode"`
+
+	// Fields are literal entry.Field paths to match.
+	Fields []entry.Field `mapstructure:"fields"`
+
+	// Patterns additionally matches every field matching a glob or regex
+	// pattern, expanded against each entry at process time. Each pattern is
+	// "<body|attributes|resource>.<rest>", where rest is either a
+	// dot-separated path that may contain "*" (matches any single key), an
+	// index or "[*]" suffix on a segment to match into an array (e.g.
+	// "events[0]" or "events[*]"), and a trailing "**" (matches the
+	// remainder of the subtree at that point), e.g. "body.http.*",
+	// "attributes.k8s.pod.**", or "body.events[*].id", or, if it starts
+	// with "regex:", a regular expression matched against the dotted path
+	// below the section, e.g. "attributes.regex:^k8s\\.pod\\..*$" (regex
+	// patterns only descend into maps). In ModeRetain, matched array
+	// elements are kept in their original relative order with unmatched
+	// positions compacted out, e.g. retaining events[0] and events[2] out
+	// of a five-element array yields a two-element array.
+	Patterns []string `mapstructure:"patterns"`
+
+	// ConditionalFields are matched only for entries satisfying their own
+	// When expression, e.g. retaining the full body for error logs while
+	// stripping it down to a handful of keys for info logs. The embedded
+	// TransformerConfig's 'if' still gates the whole operator; When is
+	// evaluated in addition to that, per field.
+	ConditionalFields []FieldCondition `mapstructure:"conditional_fields"`
+
+	// DebugMissingFields, when true, logs a sampled debug message whenever a
+	// configured field is absent from the entry it was matched against.
+	DebugMissingFields bool `mapstructure:"debug_missing_fields"`
+}
+
+// Build will build a project operator from the supplied configuration.
+func (c *ProjectOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	transformerOperator, err := c.TransformerConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Mode {
+	case ModeRetain, ModeRemove:
+	case "":
+		c.Mode = ModeRetain
+	default:
+		return nil, fmt.Errorf("project: invalid mode %q: must be %q or %q", c.Mode, ModeRetain, ModeRemove)
+	}
+
+	if len(c.Fields) == 0 && len(c.Patterns) == 0 && len(c.ConditionalFields) == 0 {
+		return nil, fmt.Errorf("project: at least one of 'fields', 'patterns', or 'conditional_fields' must be specified")
+	}
+
+	patterns := make([]fieldPattern, 0, len(c.Patterns))
+	for _, raw := range c.Patterns {
+		p, err := parseFieldPattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+
+	conditionalFields := make([]compiledFieldCondition, 0, len(c.ConditionalFields))
+	for _, fc := range c.ConditionalFields {
+		compiled, err := fc.compile()
+		if err != nil {
+			return nil, err
+		}
+		conditionalFields = append(conditionalFields, compiled)
+	}
+
+	metrics, err := newProjectMetrics(c.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure metrics: %w", err)
+	}
+
+	return &ProjectOperator{
+		TransformerOperator: transformerOperator,
+		Mode:                c.Mode,
+		Fields:              c.Fields,
+		Patterns:            patterns,
+		ConditionalFields:   conditionalFields,
+		DebugMissingFields:  c.DebugMissingFields,
+		metrics:             metrics,
+	}, nil
+}
+
+// ProjectOperator selects fields (literal, pattern matched, or conditionally
+// matched) of an entry's body, attributes, and resource, and either keeps
+// only those fields (Mode == ModeRetain) or drops only those fields
+// (Mode == ModeRemove).
+type ProjectOperator struct {
+	helper.TransformerOperator
+	Mode               Mode
+	Fields             []entry.Field
+	Patterns           []fieldPattern
+	ConditionalFields  []compiledFieldCondition
+	DebugMissingFields bool
+
+	metrics          *projectMetrics
+	missingFieldLogs uint64
+}
+
+// Process will process an entry with the configured projection.
+func (p *ProjectOperator) Process(ctx context.Context, ent *entry.Entry) error {
+	return p.ProcessWith(ctx, ent, p.Transform)
+}
+
+// resolveLiteralFields collects the declared Fields plus any
+// ConditionalFields whose When expression matches src, the entry's state
+// before Transform mutates it.
+func (p *ProjectOperator) resolveLiteralFields(src *entry.Entry) ([]entry.Field, error) {
+	fields := append([]entry.Field{}, p.Fields...)
+	for _, cond := range p.ConditionalFields {
+		matched, err := cond.matches(src)
+		if err != nil {
+			return nil, fmt.Errorf("project: failed to evaluate 'when' for %s: %w", cond.field, err)
+		}
+		if matched {
+			fields = append(fields, cond.field)
+		}
+	}
+	return fields, nil
+}
+
+// resolvePatternMatches expands every configured Patterns entry against
+// src, the entry's state before Transform mutates it.
+func (p *ProjectOperator) resolvePatternMatches(src *entry.Entry) []patternMatch {
+	var matches []patternMatch
+	for _, pattern := range p.Patterns {
+		matches = append(matches, pattern.expand(src)...)
+	}
+	return matches
+}
+
+func sectionRoot(ent *entry.Entry, sec section) interface{} {
+	switch sec {
+	case sectionBody:
+		return ent.Body
+	case sectionAttributes:
+		return ent.Attributes
+	case sectionResource:
+		return ent.Resource
+	default:
+		return nil
+	}
+}
+
+func setSectionRoot(ent *entry.Entry, sec section, val interface{}) {
+	switch sec {
+	case sectionBody:
+		ent.Body = val
+	case sectionAttributes:
+		ent.Attributes = val
+	case sectionResource:
+		ent.Resource = val
+	}
+}
+
+// Transform applies the configured projection to ent: in ModeRetain, every
+// section targeted by a literal field or matched into by a pattern is
+// rebuilt from only the matched fields (array elements kept in their
+// original relative order, with any unmatched positions compacted out),
+// leaving other sections untouched;
+// in ModeRemove, only the matched fields are deleted, leaving everything
+// else in place.
+func (p *ProjectOperator) Transform(ent *entry.Entry) error {
+	// Metrics have no context of their own to attach to here: TransformFunc
+	// carries none, so recording uses the background context like the rest
+	// of this package's self-instrumentation.
+	ctx := context.Background()
+	p.metrics.addEntryProcessed(ctx)
+
+	// src holds the entry's original values so fields can still be read
+	// from sections that ModeRetain is about to clear below.
+	src := &entry.Entry{Body: ent.Body, Attributes: ent.Attributes, Resource: ent.Resource}
+
+	literalFields, err := p.resolveLiteralFields(src)
+	if err != nil {
+		return err
+	}
+	patternMatches := p.resolvePatternMatches(src)
+
+	if p.Mode == ModeRemove {
+		for _, field := range literalFields {
+			if _, ok := field.Delete(ent); ok {
+				p.metrics.addFieldMatched(ctx, field.String())
+			} else {
+				p.metrics.addFieldMissing(ctx, field.String())
+				p.logMissingField(field.String())
+			}
+		}
+		for _, match := range patternMatches {
+			name := pathString(match.Section, match.Path)
+			newRoot, deleted := deleteAtPath(sectionRoot(ent, match.Section), match.Path)
+			if !deleted {
+				p.metrics.addFieldMissing(ctx, match.Pattern)
+				p.logMissingField(name)
+				continue
+			}
+			setSectionRoot(ent, match.Section, newRoot)
+			p.metrics.addFieldMatched(ctx, match.Pattern)
+		}
+		return nil
+	}
+
+	var touchBody, touchAttributes, touchResource bool
+	for _, field := range literalFields {
+		switch fieldSection(field) {
+		case sectionBody:
+			touchBody = true
+		case sectionAttributes:
+			touchAttributes = true
+		case sectionResource:
+			touchResource = true
+		}
+	}
+
+	var bodyTree, attributesTree, resourceTree treeNode
+	for _, match := range patternMatches {
+		name := pathString(match.Section, match.Path)
+		val, ok := getAtPath(sectionRoot(src, match.Section), match.Path)
+		if !ok {
+			p.metrics.addFieldMissing(ctx, match.Pattern)
+			p.logMissingField(name)
+			continue
+		}
+		p.metrics.addFieldMatched(ctx, match.Pattern)
+		switch match.Section {
+		case sectionBody:
+			touchBody = true
+			bodyTree.set(match.Path, val)
+		case sectionAttributes:
+			touchAttributes = true
+			attributesTree.set(match.Path, val)
+		case sectionResource:
+			touchResource = true
+			resourceTree.set(match.Path, val)
+		}
+	}
+
+	if touchBody {
+		ent.Body = bodyTree.finalize()
+	}
+	if touchAttributes {
+		ent.Attributes = attributesTree.finalize()
+	}
+	if touchResource {
+		ent.Resource = resourceTree.finalize()
+	}
+
+	for _, field := range literalFields {
+		val, ok := field.Get(src)
+		if !ok {
+			p.metrics.addFieldMissing(ctx, field.String())
+			p.logMissingField(field.String())
+			continue
+		}
+		p.metrics.addFieldMatched(ctx, field.String())
+		if err := field.Set(ent, val); err != nil {
+			return fmt.Errorf("project: failed to set field %s: %w", field, err)
+		}
+	}
+
+	if (touchBody && ent.Body == nil) || (touchAttributes && ent.Attributes == nil) || (touchResource && ent.Resource == nil) {
+		p.metrics.addEntryEmptied(ctx)
+	}
+	return nil
+}
+
+// logMissingField emits a sampled debug log when DebugMissingFields is set,
+// so operators can trace down a misconfigured rule without every absent key
+// across every entry flooding the log at normal volumes.
+func (p *ProjectOperator) logMissingField(name string) {
+	if !p.DebugMissingFields {
+		return
+	}
+	if n := atomic.AddUint64(&p.missingFieldLogs, 1); n%missingFieldLogSampleRate != 1 {
+		return
+	}
+	p.Debugf("Configured field not present on entry", zap.String("field", name))
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+type testCase struct {
+	name      string
+	expectErr bool
+	op        *ProjectOperatorConfig
+	input     func() *entry.Entry
+	output    func() *entry.Entry
+}
+
+func TestBuildAndProcess(t *testing.T) {
+	now := time.Now()
+	newTestEntry := func() *entry.Entry {
+		e := entry.New()
+		e.ObservedTimestamp = now
+		e.Timestamp = time.Unix(1586632809, 0)
+		e.Body = map[string]interface{}{
+			"key": "val",
+			"nested": map[string]interface{}{
+				"nestedkey": "nestedval",
+			},
+		}
+		return e
+	}
+
+	cases := []testCase{
+		{
+			"retain_mode_keeps_only_matched_field",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Mode = ModeRetain
+				cfg.Fields = append(cfg.Fields, entry.NewBodyField("key"))
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"key": "val",
+				}
+				return e
+			},
+		},
+		{
+			"remove_mode_drops_only_matched_field",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Mode = ModeRemove
+				cfg.Fields = append(cfg.Fields, entry.NewBodyField("key"))
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"nested": map[string]interface{}{
+						"nestedkey": "nestedval",
+					},
+				}
+				return e
+			},
+		},
+		{
+			"remove_mode_leaves_unmatched_fields_untouched",
+			false,
+			func() *ProjectOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Mode = ModeRemove
+				cfg.Fields = append(cfg.Fields, entry.NewBodyField("nested", "nestedkey"))
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"key":    "val",
+					"nested": map[string]interface{}{},
+				}
+				return e
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.op
+			cfg.OutputIDs = []string{"fake"}
+			cfg.OnError = "drop"
+			op, err := cfg.Build(testutil.Logger(t))
+			require.NoError(t, err)
+
+			project := op.(*ProjectOperator)
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, project.SetOutputs([]operator.Operator{fake}))
+			val := tc.input()
+			err = project.Process(context.Background(), val)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				fake.ExpectEntry(t, tc.output())
+			}
+		})
+	}
+}
+
+func TestBuildInvalidMode(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.OutputIDs = []string{"fake"}
+	cfg.Mode = "bogus"
+	cfg.Fields = []entry.Field{entry.NewBodyField("key")}
+
+	_, err := cfg.Build(testutil.Logger(t))
+	require.Error(t, err)
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package remove provides an operator that drops the configured fields of an
+// entry's body, attributes, and resource, leaving everything else in place.
+// It is a thin, backward-compatible alias over the project operator's
+// ModeRemove: see that package for the shared field-selection engine.
+package remove // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/remove"
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+)
+
+const operatorType = "remove"
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewRemoveOperatorConfig("") })
+}
+
+// NewRemoveOperatorConfig creates a new remove operator config with default values.
+func NewRemoveOperatorConfig(operatorID string) *RemoveOperatorConfig {
+	cfg := project.NewProjectOperatorConfig(operatorID)
+	cfg.Mode = project.ModeRemove
+	return &RemoveOperatorConfig{ProjectOperatorConfig: *cfg}
+}
+
+// RemoveOperatorConfig is the configuration of a remove operator: a project
+// operator config pinned to ModeRemove.
+type RemoveOperatorConfig struct {
+	project.ProjectOperatorConfig `mapstructure:",squash"`
+}
+
+// Build will build a remove operator from the supplied configuration.
+func (c *RemoveOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	c.ProjectOperatorConfig.Mode = project.ModeRemove
+	op, err := c.ProjectOperatorConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+	return &RemoveOperator{ProjectOperator: op.(*project.ProjectOperator)}, nil
+}
+
+// RemoveOperator drops the configured fields (literal, pattern matched, or
+// conditionally matched) of an entry's body, attributes, and resource,
+// leaving everything else untouched.
+type RemoveOperator struct {
+	*project.ProjectOperator
+}
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remove
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestBuildAndProcess(t *testing.T) {
+	now := time.Now()
+	newTestEntry := func() *entry.Entry {
+		e := entry.New()
+		e.ObservedTimestamp = now
+		e.Timestamp = time.Unix(1586632809, 0)
+		e.Body = map[string]interface{}{
+			"key":   "val",
+			"other": "value",
+		}
+		e.Attributes = map[string]interface{}{
+			"unrelated": "value",
+		}
+		return e
+	}
+
+	cfg := NewRemoveOperatorConfig("")
+	cfg.OutputIDs = []string{"fake"}
+	cfg.OnError = "drop"
+	cfg.Fields = append(cfg.Fields, entry.NewBodyField("key"))
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	removeOp := op.(*RemoveOperator)
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, removeOp.SetOutputs([]operator.Operator{fake}))
+
+	e := newTestEntry()
+	require.NoError(t, removeOp.Process(context.Background(), e))
+
+	expected := newTestEntry()
+	expected.Body = map[string]interface{}{
+		"other": "value",
+	}
+	fake.ExpectEntry(t, expected)
+}
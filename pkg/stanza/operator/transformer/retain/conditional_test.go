@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestBuildAndProcessConditionalFields(t *testing.T) {
+	now := time.Now()
+	newTestEntry := func(severity string) func() *entry.Entry {
+		return func() *entry.Entry {
+			e := entry.New()
+			e.ObservedTimestamp = now
+			e.Timestamp = time.Unix(1586632809, 0)
+			e.Body = map[string]interface{}{
+				"message":  "boom",
+				"severity": severity,
+				"stack":    "trace...",
+			}
+			return e
+		}
+	}
+
+	cases := []testCase{
+		{
+			"keeps_field_when_condition_matches",
+			false,
+			func() *RetainOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Fields = append(cfg.Fields, entry.NewBodyField("severity"))
+				cfg.ConditionalFields = []project.FieldCondition{
+					{Field: entry.NewBodyField("stack"), When: `body.severity == "error"`},
+				}
+				return cfg
+			}(),
+			newTestEntry("error"),
+			func() *entry.Entry {
+				e := newTestEntry("error")()
+				e.Body = map[string]interface{}{
+					"severity": "error",
+					"stack":    "trace...",
+				}
+				return e
+			},
+		},
+		{
+			"drops_field_when_condition_does_not_match",
+			false,
+			func() *RetainOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Fields = append(cfg.Fields, entry.NewBodyField("severity"))
+				cfg.ConditionalFields = []project.FieldCondition{
+					{Field: entry.NewBodyField("stack"), When: `body.severity == "error"`},
+				}
+				return cfg
+			}(),
+			newTestEntry("info"),
+			func() *entry.Entry {
+				e := newTestEntry("info")()
+				e.Body = map[string]interface{}{
+					"severity": "info",
+				}
+				return e
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("BuildAndProcessConditionalFields/"+tc.name, func(t *testing.T) {
+			cfg := tc.op
+			cfg.OutputIDs = []string{"fake"}
+			cfg.OnError = "drop"
+			op, err := cfg.Build(testutil.Logger(t))
+			require.NoError(t, err)
+
+			retain := op.(*RetainOperator)
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, retain.SetOutputs([]operator.Operator{fake}))
+			val := tc.input()
+			err = retain.Process(context.Background(), val)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				fake.ExpectEntry(t, tc.output())
+			}
+		})
+	}
+}
+
+func TestFieldConditionRequiresWhen(t *testing.T) {
+	cfg := defaultCfg()
+	cfg.OutputIDs = []string{"fake"}
+	cfg.ConditionalFields = []project.FieldCondition{{Field: entry.NewBodyField("stack")}}
+
+	_, err := cfg.Build(testutil.Logger(t))
+	require.Error(t, err)
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestBuildAndProcessPatterns(t *testing.T) {
+	now := time.Now()
+	newTestEntry := func() *entry.Entry {
+		e := entry.New()
+		e.ObservedTimestamp = now
+		e.Timestamp = time.Unix(1586632809, 0)
+		e.Body = map[string]interface{}{
+			"http": map[string]interface{}{
+				"status": 200,
+				"method": "GET",
+			},
+			"other": "value",
+		}
+		e.Attributes = map[string]interface{}{
+			"k8s": map[string]interface{}{
+				"pod": map[string]interface{}{
+					"name":      "my-pod",
+					"namespace": "default",
+				},
+			},
+			"unrelated": "value",
+		}
+		e.Resource = map[string]interface{}{
+			"region": "us-east-1",
+			"zone":   "us-east-1a",
+		}
+		return e
+	}
+
+	cases := []testCase{
+		{
+			"glob_single_level",
+			false,
+			func() *RetainOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{"body.http.*"}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"http": map[string]interface{}{
+						"status": 200,
+						"method": "GET",
+					},
+				}
+				return e
+			},
+		},
+		{
+			"glob_globstar_subtree",
+			false,
+			func() *RetainOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{"attributes.k8s.pod.**"}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Attributes = map[string]interface{}{
+					"k8s": map[string]interface{}{
+						"pod": map[string]interface{}{
+							"name":      "my-pod",
+							"namespace": "default",
+						},
+					},
+				}
+				return e
+			},
+		},
+		{
+			"regex_selector",
+			false,
+			func() *RetainOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{`resource.regex:^zone$`}
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Resource = map[string]interface{}{
+					"zone": "us-east-1a",
+				}
+				return e
+			},
+		},
+		{
+			"pattern_and_literal_field_combine",
+			false,
+			func() *RetainOperatorConfig {
+				cfg := defaultCfg()
+				cfg.Patterns = []string{"body.http.*"}
+				cfg.Fields = append(cfg.Fields, entry.NewBodyField("other"))
+				return cfg
+			}(),
+			newTestEntry,
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"http": map[string]interface{}{
+						"status": 200,
+						"method": "GET",
+					},
+					"other": "value",
+				}
+				return e
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run("BuildAndProcessPatterns/"+tc.name, func(t *testing.T) {
+			cfg := tc.op
+			cfg.OutputIDs = []string{"fake"}
+			cfg.OnError = "drop"
+			op, err := cfg.Build(testutil.Logger(t))
+			require.NoError(t, err)
+
+			retain := op.(*RetainOperator)
+			fake := testutil.NewFakeOutput(t)
+			require.NoError(t, retain.SetOutputs([]operator.Operator{fake}))
+			val := tc.input()
+			err = retain.Process(context.Background(), val)
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				fake.ExpectEntry(t, tc.output())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retain provides an operator that drops every field of an entry's
+// body, attributes, and resource except the ones explicitly configured to be
+// kept. It is a thin, backward-compatible alias over the project operator's
+// ModeRetain: see that package for the shared field-selection engine.
+package retain // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/retain"
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/transformer/project"
+)
+
+const operatorType = "retain"
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewRetainOperatorConfig("") })
+}
+
+// NewRetainOperatorConfig creates a new retain operator config with default values.
+func NewRetainOperatorConfig(operatorID string) *RetainOperatorConfig {
+	cfg := project.NewProjectOperatorConfig(operatorID)
+	cfg.Mode = project.ModeRetain
+	return &RetainOperatorConfig{ProjectOperatorConfig: *cfg}
+}
+
+func defaultCfg() *RetainOperatorConfig {
+	return NewRetainOperatorConfig("")
+}
+
+// RetainOperatorConfig is the configuration of a retain operator: a project
+// operator config pinned to ModeRetain.
+type RetainOperatorConfig struct {
+	project.ProjectOperatorConfig `mapstructure:",squash"`
+}
+
+// Build will build a retain operator from the supplied configuration.
+func (c *RetainOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	c.ProjectOperatorConfig.Mode = project.ModeRetain
+	op, err := c.ProjectOperatorConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+	return &RetainOperator{ProjectOperator: op.(*project.ProjectOperator)}, nil
+}
+
+// RetainOperator keeps only the configured fields (literal, pattern matched,
+// or conditionally matched) of an entry's body, attributes, and resource,
+// dropping every other field from any of those three that a literal field
+// targets or a pattern or satisfied conditional field matches into. A section
+// that nothing matches into is left as is.
+type RetainOperator struct {
+	*project.ProjectOperator
+}
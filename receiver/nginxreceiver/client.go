@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/nginxreceiver"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// stubStatus holds the seven values exposed by nginx's plain-text
+// `stub_status` module, e.g.:
+//
+//	Active connections: 3
+//	server accepts handled requests
+//	 10 10 17
+//	Reading: 0 Writing: 1 Waiting: 2
+type stubStatus struct {
+	Active   int64
+	Accepts  int64
+	Handled  int64
+	Requests int64
+	Reading  int64
+	Writing  int64
+	Waiting  int64
+}
+
+var stubStatusPattern = regexp.MustCompile(`(?s)Active connections:\s*(\d+).*?(\d+)\s+(\d+)\s+(\d+)\s*Reading:\s*(\d+)\s*Writing:\s*(\d+)\s*Waiting:\s*(\d+)`)
+
+func parseStubStatus(body []byte) (*stubStatus, error) {
+	m := stubStatusPattern.FindStringSubmatch(string(body))
+	if m == nil {
+		return nil, fmt.Errorf("response did not match the expected stub_status format")
+	}
+	fields := make([]int64, len(m)-1)
+	for i, s := range m[1:] {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stub_status value %q: %w", s, err)
+		}
+		fields[i] = v
+	}
+	return &stubStatus{
+		Active:   fields[0],
+		Accepts:  fields[1],
+		Handled:  fields[2],
+		Requests: fields[3],
+		Reading:  fields[4],
+		Writing:  fields[5],
+		Waiting:  fields[6],
+	}, nil
+}
+
+// plusStatus is the subset of the NGINX Plus API status response
+// (https://nginx.org/en/docs/http/ngx_http_api_module.html) that this
+// receiver turns into metrics.
+type plusStatus struct {
+	Connections struct {
+		Accepted int64 `json:"accepted"`
+		Dropped  int64 `json:"dropped"`
+		Active   int64 `json:"active"`
+		Idle     int64 `json:"idle"`
+	} `json:"connections"`
+	HTTP struct {
+		RequestsTotal int64                         `json:"requests_total"`
+		ServerZones   map[string]httpServerZoneStat `json:"server_zones"`
+		Upstreams     map[string]httpUpstreamStat   `json:"upstreams"`
+		Caches        map[string]httpCacheStat      `json:"caches"`
+	} `json:"http"`
+	Stream struct {
+		ServerZones map[string]streamServerZoneStat `json:"server_zones"`
+		Upstreams   map[string]streamUpstreamStat   `json:"upstreams"`
+	} `json:"stream"`
+}
+
+type statusCodeCounts struct {
+	Responses1xx int64 `json:"1xx"`
+	Responses2xx int64 `json:"2xx"`
+	Responses3xx int64 `json:"3xx"`
+	Responses4xx int64 `json:"4xx"`
+	Responses5xx int64 `json:"5xx"`
+}
+
+func (c statusCodeCounts) byRange() map[string]int64 {
+	return map[string]int64{
+		"1xx": c.Responses1xx,
+		"2xx": c.Responses2xx,
+		"3xx": c.Responses3xx,
+		"4xx": c.Responses4xx,
+		"5xx": c.Responses5xx,
+	}
+}
+
+type httpServerZoneStat struct {
+	Requests  int64            `json:"requests"`
+	Responses statusCodeCounts `json:"responses"`
+	Received  int64            `json:"received"`
+	Sent      int64            `json:"sent"`
+}
+
+type upstreamPeerStat struct {
+	Server    string           `json:"server"`
+	State     string           `json:"state"`
+	Requests  int64            `json:"requests"`
+	Responses statusCodeCounts `json:"responses"`
+}
+
+type httpUpstreamStat struct {
+	Peers []upstreamPeerStat `json:"peers"`
+}
+
+type httpCacheStat struct {
+	Hit         cacheCount `json:"hit"`
+	Miss        cacheCount `json:"miss"`
+	Expired     cacheCount `json:"expired"`
+	Stale       cacheCount `json:"stale"`
+	Updating    cacheCount `json:"updating"`
+	Revalidated cacheCount `json:"revalidated"`
+	Bypass      cacheCount `json:"bypass"`
+}
+
+type cacheCount struct {
+	Responses int64 `json:"responses"`
+}
+
+type streamServerZoneStat struct {
+	Received int64 `json:"received"`
+	Sent     int64 `json:"sent"`
+}
+
+type streamUpstreamPeerStat struct {
+	Server      string `json:"server"`
+	Connections int64  `json:"connections"`
+}
+
+type streamUpstreamStat struct {
+	Peers []streamUpstreamPeerStat `json:"peers"`
+}
+
+// nginxClient fetches and parses a single nginx status endpoint, in whatever
+// format the configured Mode calls for.
+type nginxClient struct {
+	httpClient *http.Client
+	cfg        *Config
+}
+
+func newNginxClient(httpClient *http.Client, cfg *Config) *nginxClient {
+	return &nginxClient{httpClient: httpClient, cfg: cfg}
+}
+
+// getStubStatus fetches and parses the stub_status endpoint at cfg.Endpoint.
+func (c *nginxClient) getStubStatus(ctx context.Context) (*stubStatus, error) {
+	body, err := c.get(ctx, c.cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return parseStubStatus(body)
+}
+
+// getPlusStatus fetches and parses the NGINX Plus API status endpoint at
+// cfg.Endpoint, requesting every section this receiver turns into metrics.
+func (c *nginxClient) getPlusStatus(ctx context.Context) (*plusStatus, error) {
+	url := strings.TrimRight(c.cfg.Endpoint, "/") + "/" + c.cfg.APIVersion +
+		"?fields=connections,http/requests,http/server_zones,http/upstreams,http/caches,stream/server_zones,stream/upstreams"
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	status := &plusStatus{}
+	if err := json.Unmarshal(body, status); err != nil {
+		return nil, fmt.Errorf("failed to parse NGINX Plus API response: %w", err)
+	}
+	return status, nil
+}
+
+func (c *nginxClient) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request for %s: %w", url, err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d: %s", url, resp.StatusCode, string(body))
+	}
+	return body, nil
+}
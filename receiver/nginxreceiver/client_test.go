@@ -0,0 +1,205 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+const samplePlusStatusJSON = `{
+	"connections": {"accepted": 100, "dropped": 2, "active": 3, "idle": 4},
+	"http": {
+		"requests_total": 500,
+		"server_zones": {
+			"zone1": {
+				"requests": 50,
+				"responses": {"1xx": 0, "2xx": 45, "3xx": 2, "4xx": 2, "5xx": 1},
+				"received": 1000,
+				"sent": 2000
+			}
+		},
+		"upstreams": {
+			"backend": {
+				"peers": [
+					{
+						"server": "10.0.0.1:80",
+						"state": "up",
+						"requests": 20,
+						"responses": {"1xx": 0, "2xx": 19, "3xx": 0, "4xx": 1, "5xx": 0}
+					},
+					{
+						"server": "10.0.0.2:80",
+						"state": "down",
+						"requests": 0,
+						"responses": {"1xx": 0, "2xx": 0, "3xx": 0, "4xx": 0, "5xx": 0}
+					}
+				]
+			}
+		},
+		"caches": {
+			"cache1": {
+				"hit": {"responses": 10},
+				"miss": {"responses": 3},
+				"expired": {"responses": 1},
+				"stale": {"responses": 0},
+				"updating": {"responses": 0},
+				"revalidated": {"responses": 0},
+				"bypass": {"responses": 0}
+			}
+		}
+	},
+	"stream": {
+		"server_zones": {
+			"stream1": {"received": 300, "sent": 400}
+		},
+		"upstreams": {
+			"stream_backend": {
+				"peers": [
+					{"server": "10.0.1.1:9000", "connections": 7}
+				]
+			}
+		}
+	}
+}`
+
+func TestParseStubStatus(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		expectErr bool
+		expected  *stubStatus
+	}{
+		{
+			name: "well-formed",
+			body: "Active connections: 3 \n" +
+				"server accepts handled requests\n" +
+				" 10 10 17 \n" +
+				"Reading: 0 Writing: 1 Waiting: 2 \n",
+			expected: &stubStatus{
+				Active: 3, Accepts: 10, Handled: 10, Requests: 17,
+				Reading: 0, Writing: 1, Waiting: 2,
+			},
+		},
+		{
+			name:      "missing fields",
+			body:      "Active connections: 3\n",
+			expectErr: true,
+		},
+		{
+			name:      "not stub_status at all",
+			body:      "<html>404 not found</html>",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, err := parseStubStatus([]byte(tc.body))
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, status)
+		})
+	}
+}
+
+func TestNginxClientGetStubStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Active connections: 3 \n" +
+			"server accepts handled requests\n" +
+			" 10 10 17 \n" +
+			"Reading: 0 Writing: 1 Waiting: 2 \n"))
+	}))
+	defer ts.Close()
+
+	client := newNginxClient(ts.Client(), &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL}})
+	status, err := client.getStubStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &stubStatus{Active: 3, Accepts: 10, Handled: 10, Requests: 17, Reading: 0, Writing: 1, Waiting: 2}, status)
+}
+
+func TestNginxClientGetStubStatusHTTPError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("boom"))
+	}))
+	defer ts.Close()
+
+	client := newNginxClient(ts.Client(), &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL}})
+	_, err := client.getStubStatus(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "500")
+}
+
+func TestNginxClientGetPlusStatus(t *testing.T) {
+	var requestedPath string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		_, _ = w.Write([]byte(samplePlusStatusJSON))
+	}))
+	defer ts.Close()
+
+	cfg := &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL}, APIVersion: "7"}
+	client := newNginxClient(ts.Client(), cfg)
+	status, err := client.getPlusStatus(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "/7", requestedPath)
+
+	require.EqualValues(t, 100, status.Connections.Accepted)
+	require.EqualValues(t, 2, status.Connections.Dropped)
+	require.EqualValues(t, 500, status.HTTP.RequestsTotal)
+
+	zone, ok := status.HTTP.ServerZones["zone1"]
+	require.True(t, ok)
+	require.EqualValues(t, 50, zone.Requests)
+	require.EqualValues(t, 45, zone.Responses.Responses2xx)
+
+	upstream, ok := status.HTTP.Upstreams["backend"]
+	require.True(t, ok)
+	require.Len(t, upstream.Peers, 2)
+	require.Equal(t, "up", upstream.Peers[0].State)
+
+	cache, ok := status.HTTP.Caches["cache1"]
+	require.True(t, ok)
+	require.EqualValues(t, 10, cache.Hit.Responses)
+
+	streamZone, ok := status.Stream.ServerZones["stream1"]
+	require.True(t, ok)
+	require.EqualValues(t, 300, streamZone.Received)
+
+	streamUpstream, ok := status.Stream.Upstreams["stream_backend"]
+	require.True(t, ok)
+	require.Len(t, streamUpstream.Peers, 1)
+	require.EqualValues(t, 7, streamUpstream.Peers[0].Connections)
+}
+
+func TestNginxClientGetPlusStatusMalformedJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not json"))
+	}))
+	defer ts.Close()
+
+	client := newNginxClient(ts.Client(), &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL}, APIVersion: "7"})
+	_, err := client.getPlusStatus(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/nginxreceiver"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+// Mode selects which nginx API the receiver scrapes.
+type Mode string
+
+const (
+	// ModeStubStatus scrapes the plain-text `stub_status` module exposed by
+	// open-source nginx. This is the default and preserves existing behavior.
+	ModeStubStatus Mode = "stub_status"
+	// ModePlusAPI scrapes the NGINX Plus JSON API, which exposes upstream,
+	// server zone, cache and stream metrics in addition to the stub_status set.
+	ModePlusAPI Mode = "plus_api"
+)
+
+// Config defines configuration for the nginx receiver.
+type Config struct {
+	scraperhelper.ScraperControllerSettings `mapstructure:",squash"`
+	confighttp.HTTPClientSettings           `mapstructure:",squash"`
+
+	// Mode selects the scrape mode: "stub_status" (default) or "plus_api".
+	Mode Mode `mapstructure:"mode"`
+
+	// APIVersion is the NGINX Plus API version to request, e.g. "7". Only
+	// used when Mode is "plus_api".
+	APIVersion string `mapstructure:"api_version"`
+}
+
+func (cfg *Config) Validate() error {
+	switch cfg.Mode {
+	case "", ModeStubStatus:
+		cfg.Mode = ModeStubStatus
+	case ModePlusAPI:
+		if cfg.APIVersion == "" {
+			return fmt.Errorf("api_version must be set when mode is %q", ModePlusAPI)
+		}
+	default:
+		return fmt.Errorf("invalid mode %q: must be %q or %q", cfg.Mode, ModeStubStatus, ModePlusAPI)
+	}
+
+	if cfg.Endpoint == "" {
+		return fmt.Errorf("endpoint must be specified")
+	}
+
+	return nil
+}
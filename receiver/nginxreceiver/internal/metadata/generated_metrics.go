@@ -55,10 +55,19 @@ func (m *metricImpl) Init(metric pdata.Metric) {
 }
 
 type metricStruct struct {
-	NginxConnectionsAccepted MetricIntf
-	NginxConnectionsCurrent  MetricIntf
-	NginxConnectionsHandled  MetricIntf
-	NginxRequests            MetricIntf
+	NginxConnectionsAccepted           MetricIntf
+	NginxConnectionsCurrent            MetricIntf
+	NginxConnectionsHandled            MetricIntf
+	NginxRequests                      MetricIntf
+	NginxUpstreamPeerRequests          MetricIntf
+	NginxUpstreamPeerResponses         MetricIntf
+	NginxUpstreamPeerHealth            MetricIntf
+	NginxServerZoneRequests            MetricIntf
+	NginxServerZoneResponses           MetricIntf
+	NginxServerZoneIo                  MetricIntf
+	NginxCacheRequests                 MetricIntf
+	NginxStreamUpstreamPeerConnections MetricIntf
+	NginxStreamIo                      MetricIntf
 }
 
 // Names returns a list of all the metric name strings.
@@ -68,14 +77,32 @@ func (m *metricStruct) Names() []string {
 		"nginx.connections_current",
 		"nginx.connections_handled",
 		"nginx.requests",
+		"nginx.upstream.peer.requests",
+		"nginx.upstream.peer.responses",
+		"nginx.upstream.peer.health",
+		"nginx.server_zone.requests",
+		"nginx.server_zone.responses",
+		"nginx.server_zone.io",
+		"nginx.cache.requests",
+		"nginx.stream.upstream.peer.connections",
+		"nginx.stream.io",
 	}
 }
 
 var metricsByName = map[string]MetricIntf{
-	"nginx.connections_accepted": Metrics.NginxConnectionsAccepted,
-	"nginx.connections_current":  Metrics.NginxConnectionsCurrent,
-	"nginx.connections_handled":  Metrics.NginxConnectionsHandled,
-	"nginx.requests":             Metrics.NginxRequests,
+	"nginx.connections_accepted":             Metrics.NginxConnectionsAccepted,
+	"nginx.connections_current":              Metrics.NginxConnectionsCurrent,
+	"nginx.connections_handled":              Metrics.NginxConnectionsHandled,
+	"nginx.requests":                         Metrics.NginxRequests,
+	"nginx.upstream.peer.requests":           Metrics.NginxUpstreamPeerRequests,
+	"nginx.upstream.peer.responses":          Metrics.NginxUpstreamPeerResponses,
+	"nginx.upstream.peer.health":             Metrics.NginxUpstreamPeerHealth,
+	"nginx.server_zone.requests":             Metrics.NginxServerZoneRequests,
+	"nginx.server_zone.responses":            Metrics.NginxServerZoneResponses,
+	"nginx.server_zone.io":                   Metrics.NginxServerZoneIo,
+	"nginx.cache.requests":                   Metrics.NginxCacheRequests,
+	"nginx.stream.upstream.peer.connections": Metrics.NginxStreamUpstreamPeerConnections,
+	"nginx.stream.io":                        Metrics.NginxStreamIo,
 }
 
 func (m *metricStruct) ByName(n string) MetricIntf {
@@ -84,10 +111,19 @@ func (m *metricStruct) ByName(n string) MetricIntf {
 
 func (m *metricStruct) FactoriesByName() map[string]func(pdata.Metric) {
 	return map[string]func(pdata.Metric){
-		Metrics.NginxConnectionsAccepted.Name(): Metrics.NginxConnectionsAccepted.Init,
-		Metrics.NginxConnectionsCurrent.Name():  Metrics.NginxConnectionsCurrent.Init,
-		Metrics.NginxConnectionsHandled.Name():  Metrics.NginxConnectionsHandled.Init,
-		Metrics.NginxRequests.Name():            Metrics.NginxRequests.Init,
+		Metrics.NginxConnectionsAccepted.Name():           Metrics.NginxConnectionsAccepted.Init,
+		Metrics.NginxConnectionsCurrent.Name():            Metrics.NginxConnectionsCurrent.Init,
+		Metrics.NginxConnectionsHandled.Name():            Metrics.NginxConnectionsHandled.Init,
+		Metrics.NginxRequests.Name():                      Metrics.NginxRequests.Init,
+		Metrics.NginxUpstreamPeerRequests.Name():          Metrics.NginxUpstreamPeerRequests.Init,
+		Metrics.NginxUpstreamPeerResponses.Name():         Metrics.NginxUpstreamPeerResponses.Init,
+		Metrics.NginxUpstreamPeerHealth.Name():            Metrics.NginxUpstreamPeerHealth.Init,
+		Metrics.NginxServerZoneRequests.Name():            Metrics.NginxServerZoneRequests.Init,
+		Metrics.NginxServerZoneResponses.Name():           Metrics.NginxServerZoneResponses.Init,
+		Metrics.NginxServerZoneIo.Name():                  Metrics.NginxServerZoneIo.Init,
+		Metrics.NginxCacheRequests.Name():                 Metrics.NginxCacheRequests.Init,
+		Metrics.NginxStreamUpstreamPeerConnections.Name(): Metrics.NginxStreamUpstreamPeerConnections.Init,
+		Metrics.NginxStreamIo.Name():                      Metrics.NginxStreamIo.Init,
 	}
 }
 
@@ -136,6 +172,103 @@ var Metrics = &metricStruct{
 			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
 		},
 	},
+	&metricImpl{
+		"nginx.upstream.peer.requests",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.requests")
+			metric.SetDescription("The total number of client requests forwarded to this upstream peer")
+			metric.SetUnit("requests")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.responses",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.responses")
+			metric.SetDescription("The number of responses received from this upstream peer, by status class")
+			metric.SetUnit("responses")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.upstream.peer.health",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.upstream.peer.health")
+			metric.SetDescription("Whether an upstream peer is up: 1 if its reported state (the peer_state label) is up, 0 for any other state")
+			metric.SetUnit("1")
+			metric.SetDataType(pdata.MetricDataTypeGauge)
+		},
+	},
+	&metricImpl{
+		"nginx.server_zone.requests",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.server_zone.requests")
+			metric.SetDescription("The total number of client requests received in this server zone")
+			metric.SetUnit("requests")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.server_zone.responses",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.server_zone.responses")
+			metric.SetDescription("The number of responses sent from this server zone, by status class")
+			metric.SetUnit("responses")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.server_zone.io",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.server_zone.io")
+			metric.SetDescription("The number of bytes transferred to or from clients of this server zone")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.cache.requests",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.cache.requests")
+			metric.SetDescription("The number of requests served from a cache zone, by lookup result")
+			metric.SetUnit("requests")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.stream.upstream.peer.connections",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.stream.upstream.peer.connections")
+			metric.SetDescription("The total number of stream (TCP/UDP) connections forwarded to this upstream peer")
+			metric.SetUnit("connections")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
+	&metricImpl{
+		"nginx.stream.io",
+		func(metric pdata.Metric) {
+			metric.SetName("nginx.stream.io")
+			metric.SetDescription("The number of bytes transferred to or from clients of this stream server zone")
+			metric.SetUnit("By")
+			metric.SetDataType(pdata.MetricDataTypeSum)
+			metric.Sum().SetIsMonotonic(true)
+			metric.Sum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+		},
+	},
 }
 
 // M contains a set of methods for each metric that help with
@@ -146,8 +279,35 @@ var M = Metrics
 var Labels = struct {
 	// State (The state of a connection)
 	State string
+	// Upstream (The name of the upstream group, as configured in the `upstream` block)
+	Upstream string
+	// Peer (The address (`host:port`) of the upstream peer server)
+	Peer string
+	// PeerState (The health state of an upstream peer, as reported by NGINX Plus)
+	PeerState string
+	// ServerZone (The name of the server zone, as configured by `status_zone`)
+	ServerZone string
+	// StatusRange (The HTTP response status class)
+	StatusRange string
+	// Cache (The name of the cache zone, as configured by `proxy_cache_path`)
+	Cache string
+	// CacheResult (The outcome of a cache lookup)
+	CacheResult string
+	// Direction (The direction of data transfer relative to NGINX)
+	Direction string
+	// StreamZone (The name of the stream server zone, as configured by `status_zone` in a `stream` block)
+	StreamZone string
 }{
 	"state",
+	"upstream",
+	"peer",
+	"peer_state",
+	"server_zone",
+	"status_range",
+	"cache",
+	"cache_result",
+	"direction",
+	"stream_zone",
 }
 
 // L contains the possible metric labels that can be used. L is an alias for
@@ -166,3 +326,61 @@ var LabelState = struct {
 	"writing",
 	"waiting",
 }
+
+// LabelPeerState are the possible values that the label "peer_state" can have.
+var LabelPeerState = struct {
+	Up        string
+	Down      string
+	Unavail   string
+	Checking  string
+	Unhealthy string
+}{
+	"up",
+	"down",
+	"unavail",
+	"checking",
+	"unhealthy",
+}
+
+// LabelStatusRange are the possible values that the label "status_range" can have.
+var LabelStatusRange = struct {
+	OneXx   string
+	TwoXx   string
+	ThreeXx string
+	FourXx  string
+	FiveXx  string
+}{
+	"1xx",
+	"2xx",
+	"3xx",
+	"4xx",
+	"5xx",
+}
+
+// LabelCacheResult are the possible values that the label "cache_result" can have.
+var LabelCacheResult = struct {
+	Hit         string
+	Miss        string
+	Expired     string
+	Stale       string
+	Updating    string
+	Revalidated string
+	Bypass      string
+}{
+	"hit",
+	"miss",
+	"expired",
+	"stale",
+	"updating",
+	"revalidated",
+	"bypass",
+}
+
+// LabelDirection are the possible values that the label "direction" can have.
+var LabelDirection = struct {
+	Sent     string
+	Received string
+}{
+	"sent",
+	"received",
+}
@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/nginxreceiver"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/nginxreceiver/internal/metadata"
+)
+
+// nginxScraper scrapes either nginx's stub_status endpoint or, in plus_api
+// mode, the NGINX Plus API, and turns the result into pdata.Metrics using
+// the metric definitions generated into internal/metadata.
+type nginxScraper struct {
+	cfg      *Config
+	settings component.ReceiverCreateSettings
+	client   *nginxClient
+}
+
+func newNginxScraper(cfg *Config, settings component.ReceiverCreateSettings) *nginxScraper {
+	return &nginxScraper{cfg: cfg, settings: settings}
+}
+
+func (s *nginxScraper) start(_ context.Context, host component.Host) error {
+	httpClient, err := s.cfg.HTTPClientSettings.ToClient(host.GetExtensions(), s.settings.TelemetrySettings)
+	if err != nil {
+		return fmt.Errorf("failed to build HTTP client: %w", err)
+	}
+	s.client = newNginxClient(httpClient, s.cfg)
+	return nil
+}
+
+func (s *nginxScraper) scrape(ctx context.Context) (pdata.Metrics, error) {
+	switch s.cfg.Mode {
+	case ModePlusAPI:
+		return s.scrapePlusAPI(ctx)
+	default:
+		return s.scrapeStubStatus(ctx)
+	}
+}
+
+func (s *nginxScraper) scrapeStubStatus(ctx context.Context) (pdata.Metrics, error) {
+	status, err := s.client.getStubStatus(ctx)
+	if err != nil {
+		return pdata.Metrics{}, consumererror.NewPermanent(fmt.Errorf("failed to scrape stub_status: %w", err))
+	}
+
+	b := newMetricsBuilder()
+	b.addIntSum(metadata.Metrics.NginxConnectionsAccepted, status.Accepts, nil)
+	b.addIntSum(metadata.Metrics.NginxConnectionsHandled, status.Handled, nil)
+	b.addIntSum(metadata.Metrics.NginxRequests, status.Requests, nil)
+	b.addIntGauge(metadata.Metrics.NginxConnectionsCurrent, status.Reading, map[string]string{metadata.L.State: metadata.LabelState.Reading})
+	b.addIntGauge(metadata.Metrics.NginxConnectionsCurrent, status.Writing, map[string]string{metadata.L.State: metadata.LabelState.Writing})
+	b.addIntGauge(metadata.Metrics.NginxConnectionsCurrent, status.Waiting, map[string]string{metadata.L.State: metadata.LabelState.Waiting})
+	return b.metrics, nil
+}
+
+func (s *nginxScraper) scrapePlusAPI(ctx context.Context) (pdata.Metrics, error) {
+	status, err := s.client.getPlusStatus(ctx)
+	if err != nil {
+		return pdata.Metrics{}, consumererror.NewPermanent(fmt.Errorf("failed to scrape NGINX Plus API: %w", err))
+	}
+
+	b := newMetricsBuilder()
+
+	b.addIntSum(metadata.Metrics.NginxConnectionsAccepted, status.Connections.Accepted, nil)
+	b.addIntSum(metadata.Metrics.NginxConnectionsHandled, status.Connections.Accepted-status.Connections.Dropped, nil)
+	b.addIntSum(metadata.Metrics.NginxRequests, status.HTTP.RequestsTotal, nil)
+	b.addIntGauge(metadata.Metrics.NginxConnectionsCurrent, status.Connections.Active, map[string]string{metadata.L.State: metadata.LabelState.Active})
+
+	for zone, stat := range status.HTTP.ServerZones {
+		b.addIntSum(metadata.Metrics.NginxServerZoneRequests, stat.Requests, map[string]string{metadata.L.ServerZone: zone})
+		b.addIntSum(metadata.Metrics.NginxServerZoneIo, stat.Received, map[string]string{metadata.L.ServerZone: zone, metadata.L.Direction: metadata.LabelDirection.Received})
+		b.addIntSum(metadata.Metrics.NginxServerZoneIo, stat.Sent, map[string]string{metadata.L.ServerZone: zone, metadata.L.Direction: metadata.LabelDirection.Sent})
+		for statusRange, count := range stat.Responses.byRange() {
+			b.addIntSum(metadata.Metrics.NginxServerZoneResponses, count, map[string]string{metadata.L.ServerZone: zone, metadata.L.StatusRange: statusRange})
+		}
+	}
+
+	for upstream, stat := range status.HTTP.Upstreams {
+		for _, peer := range stat.Peers {
+			b.addIntSum(metadata.Metrics.NginxUpstreamPeerRequests, peer.Requests, map[string]string{metadata.L.Upstream: upstream, metadata.L.Peer: peer.Server})
+			b.addIntGauge(metadata.Metrics.NginxUpstreamPeerHealth, peerHealth(peer.State), map[string]string{metadata.L.Upstream: upstream, metadata.L.Peer: peer.Server, metadata.L.PeerState: peer.State})
+			for statusRange, count := range peer.Responses.byRange() {
+				b.addIntSum(metadata.Metrics.NginxUpstreamPeerResponses, count, map[string]string{metadata.L.Upstream: upstream, metadata.L.Peer: peer.Server, metadata.L.StatusRange: statusRange})
+			}
+		}
+	}
+
+	for cache, stat := range status.HTTP.Caches {
+		b.addIntSum(metadata.Metrics.NginxCacheRequests, stat.Hit.Responses, map[string]string{metadata.L.Cache: cache, metadata.L.CacheResult: metadata.LabelCacheResult.Hit})
+		b.addIntSum(metadata.Metrics.NginxCacheRequests, stat.Miss.Responses, map[string]string{metadata.L.Cache: cache, metadata.L.CacheResult: metadata.LabelCacheResult.Miss})
+		b.addIntSum(metadata.Metrics.NginxCacheRequests, stat.Expired.Responses, map[string]string{metadata.L.Cache: cache, metadata.L.CacheResult: metadata.LabelCacheResult.Expired})
+		b.addIntSum(metadata.Metrics.NginxCacheRequests, stat.Stale.Responses, map[string]string{metadata.L.Cache: cache, metadata.L.CacheResult: metadata.LabelCacheResult.Stale})
+		b.addIntSum(metadata.Metrics.NginxCacheRequests, stat.Updating.Responses, map[string]string{metadata.L.Cache: cache, metadata.L.CacheResult: metadata.LabelCacheResult.Updating})
+		b.addIntSum(metadata.Metrics.NginxCacheRequests, stat.Revalidated.Responses, map[string]string{metadata.L.Cache: cache, metadata.L.CacheResult: metadata.LabelCacheResult.Revalidated})
+		b.addIntSum(metadata.Metrics.NginxCacheRequests, stat.Bypass.Responses, map[string]string{metadata.L.Cache: cache, metadata.L.CacheResult: metadata.LabelCacheResult.Bypass})
+	}
+
+	for zone, stat := range status.Stream.ServerZones {
+		b.addIntSum(metadata.Metrics.NginxStreamIo, stat.Received, map[string]string{metadata.L.StreamZone: zone, metadata.L.Direction: metadata.LabelDirection.Received})
+		b.addIntSum(metadata.Metrics.NginxStreamIo, stat.Sent, map[string]string{metadata.L.StreamZone: zone, metadata.L.Direction: metadata.LabelDirection.Sent})
+	}
+
+	for upstream, stat := range status.Stream.Upstreams {
+		for _, peer := range stat.Peers {
+			b.addIntSum(metadata.Metrics.NginxStreamUpstreamPeerConnections, peer.Connections, map[string]string{metadata.L.Upstream: upstream, metadata.L.Peer: peer.Server})
+		}
+	}
+
+	return b.metrics, nil
+}
+
+// peerHealth maps an NGINX Plus peer state string to the 0/1 gauge value
+// nginx.upstream.peer.health reports for that state.
+func peerHealth(state string) int64 {
+	if state == metadata.LabelPeerState.Up {
+		return 1
+	}
+	return 0
+}
+
+// metricsBuilder accumulates data points for a single scrape into one
+// pdata.Metrics, appending a fresh Metric (initialized via the generated
+// MetricIntf) the first time each metric definition is used.
+type metricsBuilder struct {
+	metrics pdata.Metrics
+	now     pdata.Timestamp
+	ilm     pdata.InstrumentationLibraryMetrics
+	built   map[string]pdata.Metric
+}
+
+func newMetricsBuilder() *metricsBuilder {
+	metrics := pdata.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	ilm := rm.InstrumentationLibraryMetrics().AppendEmpty()
+	return &metricsBuilder{
+		metrics: metrics,
+		now:     pdata.NewTimestampFromTime(time.Now()),
+		ilm:     ilm,
+		built:   make(map[string]pdata.Metric),
+	}
+}
+
+func (b *metricsBuilder) metricFor(def metadata.MetricIntf) pdata.Metric {
+	if m, ok := b.built[def.Name()]; ok {
+		return m
+	}
+	m := b.ilm.Metrics().AppendEmpty()
+	def.Init(m)
+	b.built[def.Name()] = m
+	return m
+}
+
+func (b *metricsBuilder) addIntSum(def metadata.MetricIntf, value int64, labels map[string]string) {
+	dp := b.metricFor(def).Sum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(b.now)
+	dp.SetIntVal(value)
+	for k, v := range labels {
+		dp.LabelsMap().Insert(k, v)
+	}
+}
+
+func (b *metricsBuilder) addIntGauge(def metadata.MetricIntf, value int64, labels map[string]string) {
+	dp := b.metricFor(def).Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(b.now)
+	dp.SetIntVal(value)
+	for k, v := range labels {
+		dp.LabelsMap().Insert(k, v)
+	}
+}
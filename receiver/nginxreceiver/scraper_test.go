@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nginxreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/nginxreceiver/internal/metadata"
+)
+
+// findDataPoints collects every int data point across every metric named name,
+// keyed by their label set joined with "=" and ",", so tests can assert
+// against a specific series without depending on emission order.
+func findDataPoints(t *testing.T, metrics pdata.Metrics, name string) map[string]int64 {
+	t.Helper()
+	found := make(map[string]int64)
+	rms := metrics.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ilms := rms.At(i).InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ms := ilms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				if m.Name() != name {
+					continue
+				}
+				var dps pdata.NumberDataPointSlice
+				switch m.DataType() {
+				case pdata.MetricDataTypeSum:
+					dps = m.Sum().DataPoints()
+				case pdata.MetricDataTypeGauge:
+					dps = m.Gauge().DataPoints()
+				}
+				for d := 0; d < dps.Len(); d++ {
+					dp := dps.At(d)
+					key := ""
+					dp.LabelsMap().Sort().Range(func(k, v string) bool {
+						key += k + "=" + v + ","
+						return true
+					})
+					found[key] = dp.IntVal()
+				}
+			}
+		}
+	}
+	return found
+}
+
+func newTestScraper(t *testing.T, ts *httptest.Server, cfg *Config) *nginxScraper {
+	t.Helper()
+	s := newNginxScraper(cfg, component.ReceiverCreateSettings{})
+	s.client = newNginxClient(ts.Client(), cfg)
+	return s
+}
+
+func TestScraperScrapeStubStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("Active connections: 3 \n" +
+			"server accepts handled requests\n" +
+			" 10 9 17 \n" +
+			"Reading: 0 Writing: 1 Waiting: 2 \n"))
+	}))
+	defer ts.Close()
+
+	cfg := &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL}, Mode: ModeStubStatus}
+	s := newTestScraper(t, ts, cfg)
+
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	accepted := findDataPoints(t, metrics, metadata.Metrics.NginxConnectionsAccepted.Name())
+	require.Equal(t, map[string]int64{"": 10}, accepted)
+
+	handled := findDataPoints(t, metrics, metadata.Metrics.NginxConnectionsHandled.Name())
+	require.Equal(t, map[string]int64{"": 9}, handled)
+
+	requests := findDataPoints(t, metrics, metadata.Metrics.NginxRequests.Name())
+	require.Equal(t, map[string]int64{"": 17}, requests)
+
+	current := findDataPoints(t, metrics, metadata.Metrics.NginxConnectionsCurrent.Name())
+	require.Equal(t, map[string]int64{
+		"state=reading,": 0,
+		"state=writing,": 1,
+		"state=waiting,": 2,
+	}, current)
+}
+
+func TestScraperScrapeStubStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	cfg := &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL}, Mode: ModeStubStatus}
+	s := newTestScraper(t, ts, cfg)
+
+	_, err := s.scrape(context.Background())
+	require.Error(t, err)
+}
+
+func TestScraperScrapePlusAPI(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(samplePlusStatusJSON))
+	}))
+	defer ts.Close()
+
+	cfg := &Config{HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: ts.URL}, Mode: ModePlusAPI, APIVersion: "7"}
+	s := newTestScraper(t, ts, cfg)
+
+	metrics, err := s.scrape(context.Background())
+	require.NoError(t, err)
+
+	current := findDataPoints(t, metrics, metadata.Metrics.NginxConnectionsCurrent.Name())
+	require.Equal(t, map[string]int64{"state=active,": 3}, current)
+
+	zoneRequests := findDataPoints(t, metrics, metadata.Metrics.NginxServerZoneRequests.Name())
+	require.Equal(t, map[string]int64{"server_zone=zone1,": 50}, zoneRequests)
+
+	zoneIO := findDataPoints(t, metrics, metadata.Metrics.NginxServerZoneIo.Name())
+	require.Equal(t, map[string]int64{
+		"direction=received,server_zone=zone1,": 1000,
+		"direction=sent,server_zone=zone1,":     2000,
+	}, zoneIO)
+
+	peerRequests := findDataPoints(t, metrics, metadata.Metrics.NginxUpstreamPeerRequests.Name())
+	require.Equal(t, map[string]int64{
+		"peer=10.0.0.1:80,upstream=backend,": 20,
+		"peer=10.0.0.2:80,upstream=backend,": 0,
+	}, peerRequests)
+
+	peerHealthDPs := findDataPoints(t, metrics, metadata.Metrics.NginxUpstreamPeerHealth.Name())
+	require.Equal(t, map[string]int64{
+		"peer=10.0.0.1:80,peer_state=up,upstream=backend,":   1,
+		"peer=10.0.0.2:80,peer_state=down,upstream=backend,": 0,
+	}, peerHealthDPs)
+
+	cacheRequests := findDataPoints(t, metrics, metadata.Metrics.NginxCacheRequests.Name())
+	require.Equal(t, int64(10), cacheRequests["cache=cache1,cache_result=hit,"])
+	require.Equal(t, int64(3), cacheRequests["cache=cache1,cache_result=miss,"])
+
+	streamIO := findDataPoints(t, metrics, metadata.Metrics.NginxStreamIo.Name())
+	require.Equal(t, map[string]int64{
+		"direction=received,stream_zone=stream1,": 300,
+		"direction=sent,stream_zone=stream1,":     400,
+	}, streamIO)
+
+	streamPeerConns := findDataPoints(t, metrics, metadata.Metrics.NginxStreamUpstreamPeerConnections.Name())
+	require.Equal(t, map[string]int64{"peer=10.0.1.1:9000,upstream=stream_backend,": 7}, streamPeerConns)
+}
+
+func TestPeerHealth(t *testing.T) {
+	require.EqualValues(t, 1, peerHealth(metadata.LabelPeerState.Up))
+	require.EqualValues(t, 0, peerHealth(metadata.LabelPeerState.Down))
+	require.EqualValues(t, 0, peerHealth("unknown"))
+}